@@ -0,0 +1,182 @@
+package m
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ErrOverflow is returned by SumChecked when the sum does not fit in the value type.
+var ErrOverflow = errors.New("integer overflow")
+
+/*
+Min returns a key with the smallest value in the map and that value. ok is false if the map is empty.
+If several keys share the smallest value, one of them is returned arbitrarily.
+
+Example
+
+	input := map[string]int{"a": 3, "b": 1, "c": 2}
+	key, value, ok := m.Min(input)
+	// key == "b", value == 1, ok == true
+*/
+func Min[K comparable, V constraints.Integer | constraints.Float](input map[K]V) (key K, value V, ok bool) {
+	for k, v := range input {
+		if !ok || v < value {
+			key, value, ok = k, v, true
+		}
+	}
+
+	return key, value, ok
+}
+
+/*
+Max returns a key with the largest value in the map and that value. ok is false if the map is empty.
+If several keys share the largest value, one of them is returned arbitrarily.
+
+Example
+
+	input := map[string]int{"a": 3, "b": 1, "c": 2}
+	key, value, ok := m.Max(input)
+	// key == "a", value == 3, ok == true
+*/
+func Max[K comparable, V constraints.Integer | constraints.Float](input map[K]V) (key K, value V, ok bool) {
+	for k, v := range input {
+		if !ok || v > value {
+			key, value, ok = k, v, true
+		}
+	}
+
+	return key, value, ok
+}
+
+/*
+Mean returns the arithmetic mean of the values in the map. ok is false if the map is empty.
+The values are summed as float64, so integer sums cannot overflow.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2}
+	mean, ok := m.Mean(input)
+	// mean == 1.5, ok == true
+*/
+func Mean[K comparable, V constraints.Integer | constraints.Float](input map[K]V) (float64, bool) {
+	if len(input) == 0 {
+		return 0, false
+	}
+
+	var sum float64
+	for _, v := range input {
+		sum += float64(v)
+	}
+
+	return sum / float64(len(input)), true
+}
+
+/*
+Median returns the median of the values in the map, the mean of the two middle values if their number is even.
+ok is false if the map is empty.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 5, "c": 2, "d": 4}
+	median, ok := m.Median(input)
+	// median == 3, ok == true
+*/
+func Median[K comparable, V constraints.Integer | constraints.Float](input map[K]V) (float64, bool) {
+	return Percentile(input, 50)
+}
+
+/*
+Percentile returns the p-th percentile of the values in the map, interpolating linearly between the two closest
+values. ok is false if the map is empty. It panics if p is not between 0 and 100.
+
+Example
+
+	input := map[string]int{"a": 10, "b": 20, "c": 30, "d": 40, "e": 50}
+	p90, ok := m.Percentile(input, 90)
+	// p90 == 46, ok == true
+*/
+func Percentile[K comparable, V constraints.Integer | constraints.Float](input map[K]V, p float64) (float64, bool) {
+	if !(p >= 0 && p <= 100) {
+		panic("percentile must be between 0 and 100")
+	}
+
+	if len(input) == 0 {
+		return 0, false
+	}
+
+	values := make([]float64, 0, len(input))
+	for _, v := range input {
+		values = append(values, float64(v))
+	}
+
+	slices.Sort(values)
+
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return values[lower] + (values[upper]-values[lower])*(rank-float64(lower)), true
+}
+
+/*
+CountBy returns how many entries of the map fall in each group returned by the function.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2, "c": 3}
+	result := m.CountBy(input, func(key string, value int) bool { return value%2 == 0 })
+	// result == map[bool]int{false: 2, true: 1}
+*/
+func CountBy[K comparable, V any, G comparable](input map[K]V, fn func(key K, value V) G) map[G]int {
+	result := make(map[G]int)
+	for k, v := range input {
+		result[fn(k, v)]++
+	}
+
+	return result
+}
+
+/*
+SumChecked returns the sum of the values in the map, or ErrOverflow if the sum does not fit in the value type.
+Unlike Sum, it never silently wraps around.
+
+Example
+
+	input := map[string]int8{"a": 100, "b": 100}
+	_, err := m.SumChecked(input)
+	// errors.Is(err, m.ErrOverflow) == true
+*/
+func SumChecked[K comparable, V constraints.Integer](input map[K]V) (V, error) {
+	var positives, negatives []V
+	for _, v := range input {
+		if v < 0 {
+			negatives = append(negatives, v)
+		} else {
+			positives = append(positives, v)
+		}
+	}
+
+	// Adding a negative value to a non-negative sum, or the reverse, cannot overflow. Alternating that way keeps
+	// every partial sum in range unless the total is out of range, so the result does not depend on map order.
+	var result V
+	for len(positives) > 0 || len(negatives) > 0 {
+		var v V
+		if (result >= 0 && len(negatives) > 0) || len(positives) == 0 {
+			v, negatives = negatives[0], negatives[1:]
+		} else {
+			v, positives = positives[0], positives[1:]
+		}
+
+		sum := result + v
+		if (v > 0 && sum < result) || (v < 0 && sum > result) {
+			return 0, ErrOverflow
+		}
+
+		result = sum
+	}
+
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package m
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	t.Run("Min and Max", func(t *testing.T) {
+		input := map[string]int{"a": 3, "b": -1, "c": 2}
+
+		key, value, ok := Min(input)
+		assert.Equal(t, "b", key)
+		assert.Equal(t, -1, value)
+		assert.True(t, ok)
+
+		key, value, ok = Max(input)
+		assert.Equal(t, "a", key)
+		assert.Equal(t, 3, value)
+		assert.True(t, ok)
+
+		_, _, ok = Min(map[string]float64{})
+		assert.False(t, ok)
+		_, _, ok = Max(map[string]float64{})
+		assert.False(t, ok)
+	})
+
+	t.Run("Mean", func(t *testing.T) {
+		mean, ok := Mean(map[string]int{"a": 1, "b": 2})
+		assert.True(t, ok)
+		assert.InDelta(t, 1.5, mean, 1e-9)
+
+		mean, ok = Mean(map[string]int8{"a": 100, "b": 100})
+		assert.True(t, ok)
+		assert.InDelta(t, 100, mean, 1e-9)
+
+		_, ok = Mean(map[string]int{})
+		assert.False(t, ok)
+	})
+
+	t.Run("Median", func(t *testing.T) {
+		median, ok := Median(map[string]int{"a": 1, "b": 5, "c": 2, "d": 4})
+		assert.True(t, ok)
+		assert.InDelta(t, 3, median, 1e-9)
+
+		median, _ = Median(map[string]float32{"a": 1.5, "b": 9, "c": 2})
+		assert.InDelta(t, 2, median, 1e-9)
+
+		_, ok = Median(map[string]int{})
+		assert.False(t, ok)
+	})
+
+	t.Run("Percentile", func(t *testing.T) {
+		input := map[string]int{"a": 10, "b": 20, "c": 30, "d": 40, "e": 50}
+
+		for p, expected := range map[float64]float64{0: 10, 25: 20, 90: 46, 100: 50} {
+			value, ok := Percentile(input, p)
+			assert.True(t, ok)
+			assert.InDelta(t, expected, value, 1e-9, "p%v", p)
+		}
+
+		value, _ := Percentile(map[string]int{"a": 7}, 99)
+		assert.InDelta(t, 7, value, 1e-9)
+
+		_, ok := Percentile(map[string]int{}, 50)
+		assert.False(t, ok)
+
+		assert.Panics(t, func() { Percentile(input, -1) })
+		assert.Panics(t, func() { Percentile(input, 101) })
+		assert.Panics(t, func() { Percentile(input, math.NaN()) })
+	})
+
+	t.Run("CountBy", func(t *testing.T) {
+		input := map[string]int{"a": 1, "b": 2, "c": 3}
+		result := CountBy(input, func(_ string, value int) bool { return value%2 == 0 })
+
+		assert.Equal(t, map[bool]int{false: 2, true: 1}, result)
+		assert.Empty(t, CountBy(map[string]int{}, func(key string, _ int) string { return key }))
+	})
+
+	t.Run("SumChecked", func(t *testing.T) {
+		sum, err := SumChecked(map[string]int{"a": 1, "b": 2, "c": 3})
+		assert.NoError(t, err)
+		assert.Equal(t, 6, sum)
+
+		_, err = SumChecked(map[string]int8{"a": 100, "b": 100})
+		assert.ErrorIs(t, err, ErrOverflow)
+
+		_, err = SumChecked(map[string]int8{"a": -100, "b": -100})
+		assert.ErrorIs(t, err, ErrOverflow)
+
+		_, err = SumChecked(map[string]uint8{"a": 200, "b": 100})
+		assert.ErrorIs(t, err, ErrOverflow)
+
+		sum8, err := SumChecked(map[string]uint8{"a": 200, "b": 55})
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(255), sum8)
+
+		// The total fits even though some partial sums would not, whatever the iteration order.
+		for range 20 {
+			sum8, err := SumChecked(map[string]int8{"a": 100, "b": 100, "c": -100, "d": -90})
+			assert.NoError(t, err)
+			assert.Equal(t, int8(10), sum8)
+		}
+
+		sum, err = SumChecked(map[string]int{})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sum)
+	})
+}
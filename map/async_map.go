@@ -1,6 +1,10 @@
 package m
 
-import "sync"
+import (
+	"context"
+	"iter"
+	"sync"
+)
 
 /*
 AsyncMap is a generic threadsafe map. It can be used as a drop-in replacement for sync.Map
@@ -13,7 +17,27 @@ Example
 	AsyncMap.Load("a") // 1, true
 */
 type AsyncMap[T comparable, V any] struct {
-	item sync.Map
+	// item holds a *V per key, so that entries can be compared and swapped by identity whatever V is.
+	item   sync.Map
+	flight SingleFlight[T, V]
+	// snapshot is read-locked by every write if consistent is set, and locked by Snapshot and Restore while they
+	// copy the entries.
+	snapshot   sync.RWMutex
+	hub        watchHub[T, V]
+	consistent bool
+}
+
+/*
+AsyncMapOptions configures an AsyncMap.
+
+Example
+
+	opts := m.AsyncMapOptions{ConsistentSnapshots: true}
+*/
+type AsyncMapOptions struct {
+	// ConsistentSnapshots makes Snapshot and Restore atomic with respect to writes. Every write then takes a
+	// shared read lock, which costs sync.Map some of its write scalability on many cores.
+	ConsistentSnapshots bool
 }
 
 // NewAsyncMap returns a new pointer to an asyncMap.
@@ -21,6 +45,17 @@ func NewAsyncMap[T comparable, V any]() *AsyncMap[T, V] {
 	return &AsyncMap[T, V]{}
 }
 
+/*
+NewAsyncMapWithOptions returns a new pointer to an AsyncMap configured with opts.
+
+Example
+
+	asyncMap := m.NewAsyncMapWithOptions[string, int](m.AsyncMapOptions{ConsistentSnapshots: true})
+*/
+func NewAsyncMapWithOptions[T comparable, V any](opts AsyncMapOptions) *AsyncMap[T, V] {
+	return &AsyncMap[T, V]{consistent: opts.ConsistentSnapshots}
+}
+
 /*
 Store sets the value for a key.
 
@@ -30,7 +65,17 @@ Example
 	asyncMap.Store("a", 1)
 */
 func (m *AsyncMap[T, V]) Store(key T, value V) {
-	m.item.Store(key, value)
+	watched := m.beginWrite()
+	defer m.endWrite(watched)
+
+	if !watched {
+		m.item.Store(key, &value)
+
+		return
+	}
+
+	previous, loaded := m.item.Swap(key, &value)
+	m.publishPut(key, previous, loaded, value)
 }
 
 /*
@@ -49,7 +94,7 @@ func (m *AsyncMap[T, V]) Load(key T) (value V, ok bool) {
 		return value, ok
 	}
 
-	return v.(V), ok //nolint:errcheck
+	return *v.(*V), ok //nolint:errcheck
 }
 
 /*
@@ -62,9 +107,15 @@ Example
 	asyncMap.LoadOrStore("a", 2) // 1, true
 */
 func (m *AsyncMap[T, V]) LoadOrStore(key T, value V) (V, bool) {
-	v, loaded := m.item.LoadOrStore(key, value)
+	watched := m.beginWrite()
+	defer m.endWrite(watched)
 
-	return v.(V), loaded //nolint:errcheck
+	v, loaded := m.item.LoadOrStore(key, &value)
+	if watched && !loaded {
+		m.publishPut(key, nil, false, value)
+	}
+
+	return *v.(*V), loaded //nolint:errcheck
 }
 
 /*
@@ -78,12 +129,19 @@ Example
 	asyncMap.LoadAndDelete("a") // 0, false
 */
 func (m *AsyncMap[T, V]) LoadAndDelete(key T) (value V, ok bool) {
+	watched := m.beginWrite()
+	defer m.endWrite(watched)
+
 	v, ok := m.item.LoadAndDelete(key)
 	if !ok {
 		return value, ok
 	}
 
-	return v.(V), ok //nolint:errcheck
+	if watched {
+		m.publishDelete(key, v)
+	}
+
+	return *v.(*V), ok //nolint:errcheck
 }
 
 /*
@@ -96,7 +154,18 @@ Example
 	asyncMap.Delete("a")
 */
 func (m *AsyncMap[T, V]) Delete(key T) {
-	m.item.Delete(key)
+	watched := m.beginWrite()
+	defer m.endWrite(watched)
+
+	if !watched {
+		m.item.Delete(key)
+
+		return
+	}
+
+	if v, ok := m.item.LoadAndDelete(key); ok {
+		m.publishDelete(key, v)
+	}
 }
 
 /*
@@ -115,6 +184,406 @@ Example
 */
 func (m *AsyncMap[T, V]) Range(fn func(key T, value V) bool) {
 	m.item.Range(func(key, value any) bool {
-		return fn(key.(T), value.(V)) //nolint:errcheck
+		return fn(key.(T), *value.(*V)) //nolint:errcheck
+	})
+}
+
+/*
+All returns an iterator over the keys and values present in the map. Like Range, it does not correspond to a
+consistent snapshot of the map.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Store("a", 1)
+	for key, value := range asyncMap.All() {
+		fmt.Printf("%s: %d", key, value)
+	}
+*/
+func (m *AsyncMap[T, V]) All() iter.Seq2[T, V] {
+	return m.Range
+}
+
+/*
+Swap swaps the value for a key and returns the previous value if any. The loaded result reports whether the key was present.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Swap("a", 1) // 0, false
+	asyncMap.Swap("a", 2) // 1, true
+*/
+func (m *AsyncMap[T, V]) Swap(key T, value V) (previous V, loaded bool) {
+	watched := m.beginWrite()
+	defer m.endWrite(watched)
+
+	v, loaded := m.item.Swap(key, &value)
+	if watched {
+		m.publishPut(key, v, loaded, value)
+	}
+
+	if !loaded {
+		return previous, loaded
+	}
+
+	return *v.(*V), loaded //nolint:errcheck
+}
+
+// comparer is implemented by the concurrent maps of this package.
+type comparer[T comparable, V any] interface {
+	compareAndSwap(key T, old, value V, equal func(a, b V) bool) bool
+	compareAndDelete(key T, old V, equal func(a, b V) bool) bool
+}
+
+/*
+CompareAndSwap stores value for a key if the value currently stored in the map is equal to old.
+It works with AsyncMap and ShardedMap.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Store("a", 1)
+	m.CompareAndSwap(asyncMap, "a", 2, 3) // false
+	m.CompareAndSwap(asyncMap, "a", 1, 3) // true
+*/
+func CompareAndSwap[T comparable, V comparable](m comparer[T, V], key T, old, value V) bool {
+	return m.compareAndSwap(key, old, value, equal[V])
+}
+
+/*
+CompareAndDelete deletes the entry for a key if its value is equal to old.
+It works with AsyncMap and ShardedMap.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Store("a", 1)
+	m.CompareAndDelete(asyncMap, "a", 2) // false
+	m.CompareAndDelete(asyncMap, "a", 1) // true
+*/
+func CompareAndDelete[T comparable, V comparable](m comparer[T, V], key T, old V) bool {
+	return m.compareAndDelete(key, old, equal[V])
+}
+
+func equal[V comparable](a, b V) bool {
+	return a == b
+}
+
+func (m *AsyncMap[T, V]) compareAndSwap(key T, old, value V, equal func(a, b V) bool) bool {
+	watched := m.beginWrite()
+	defer m.endWrite(watched)
+
+	for {
+		v, ok := m.item.Load(key)
+		if !ok || !equal(*v.(*V), old) { //nolint:errcheck
+			return false
+		}
+
+		if m.item.CompareAndSwap(key, v, &value) {
+			if watched {
+				m.publishPut(key, v, true, value)
+			}
+
+			return true
+		}
+	}
+}
+
+func (m *AsyncMap[T, V]) compareAndDelete(key T, old V, equal func(a, b V) bool) bool {
+	watched := m.beginWrite()
+	defer m.endWrite(watched)
+
+	for {
+		v, ok := m.item.Load(key)
+		if !ok || !equal(*v.(*V), old) { //nolint:errcheck
+			return false
+		}
+
+		if m.item.CompareAndDelete(key, v) {
+			if watched {
+				m.publishDelete(key, v)
+			}
+
+			return true
+		}
+	}
+}
+
+/*
+Clear deletes all the entries.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Store("a", 1)
+	asyncMap.Clear()
+	asyncMap.Len() // 0
+*/
+func (m *AsyncMap[T, V]) Clear() {
+	watched := m.beginWrite()
+	defer m.endWrite(watched)
+
+	if !watched {
+		m.item.Clear()
+
+		return
+	}
+
+	m.item.Range(func(key, value any) bool {
+		m.item.Delete(key)
+		m.publishDelete(key.(T), value) //nolint:errcheck
+
+		return true
+	})
+}
+
+/*
+Len returns the number of entries. It walks the whole map, so the result is only a snapshot under concurrent writes.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Store("a", 1)
+	asyncMap.Len() // 1
+*/
+func (m *AsyncMap[T, V]) Len() int {
+	n := 0
+	m.item.Range(func(_, _ any) bool {
+		n++
+
+		return true
+	})
+
+	return n
+}
+
+/*
+Keys returns a snapshot of the keys in the map.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Store("a", 1)
+	asyncMap.Store("b", 2)
+	asyncMap.Keys() // []string{"a", "b"}
+*/
+func (m *AsyncMap[T, V]) Keys() []T {
+	var result []T
+	m.Range(func(key T, _ V) bool {
+		result = append(result, key)
+
+		return true
+	})
+
+	return result
+}
+
+/*
+Values returns a snapshot of the values in the map.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Store("a", 1)
+	asyncMap.Store("b", 2)
+	asyncMap.Values() // []int{1, 2}
+*/
+func (m *AsyncMap[T, V]) Values() []V {
+	var result []V
+	m.Range(func(_ T, value V) bool {
+		result = append(result, value)
+
+		return true
 	})
+
+	return result
+}
+
+/*
+Compute atomically replaces the value for a key with the result of fn. fn receives the current value and whether
+the key was present, and returns the new value and whether to keep it; returning false deletes the key.
+Compute returns the new value and whether the key is present afterwards.
+
+fn may be called more than once if other goroutines write the key concurrently, so it must not have side effects.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	increment := func(value int, loaded bool) (int, bool) {
+		return value + 1, true
+	}
+	asyncMap.Compute("a", increment) // 1, true
+	asyncMap.Compute("a", increment) // 2, true
+*/
+func (m *AsyncMap[T, V]) Compute(key T, fn func(value V, loaded bool) (V, bool)) (value V, ok bool) {
+	for {
+		var current V
+
+		previous, loaded := m.item.Load(key)
+		if loaded {
+			current = *previous.(*V) //nolint:errcheck
+		}
+
+		value, ok = fn(current, loaded)
+		if m.commit(key, previous, loaded, value, ok) {
+			return value, ok
+		}
+	}
+}
+
+/*
+ComputeIfAbsent stores the result of fn if the key is not present and returns the value stored in the map.
+fn returns the value and whether to store it. The loaded result is true if the value was already present.
+fn may be called by several goroutines racing for the same key, but only one result is stored; use LoadOrCompute
+if fn must run only once.
+
+Example
+
+	asyncMap := NewAsyncMap[string, []int]()
+	asyncMap.ComputeIfAbsent("a", func() ([]int, bool) { return []int{1}, true }) // []int{1}, false
+	asyncMap.ComputeIfAbsent("a", func() ([]int, bool) { return []int{2}, true }) // []int{1}, true
+*/
+func (m *AsyncMap[T, V]) ComputeIfAbsent(key T, fn func() (V, bool)) (value V, loaded bool) {
+	if v, ok := m.item.Load(key); ok {
+		return *v.(*V), true //nolint:errcheck
+	}
+
+	value, ok := fn()
+	if !ok {
+		var zero V
+
+		return zero, false
+	}
+
+	return m.LoadOrStore(key, value)
+}
+
+/*
+ComputeIfPresent atomically replaces the value for a key with the result of fn if the key is present.
+fn returns the new value and whether to keep it; returning false deletes the key.
+ComputeIfPresent returns the new value and whether the key is present afterwards.
+
+fn may be called more than once if other goroutines write the key concurrently, so it must not have side effects.
+
+Example
+
+	asyncMap := NewAsyncMap[string, []int]()
+	asyncMap.Store("a", []int{1})
+	asyncMap.ComputeIfPresent("a", func(value []int) ([]int, bool) {
+		return append(slices.Clone(value), 2), true
+	}) // []int{1, 2}, true
+*/
+func (m *AsyncMap[T, V]) ComputeIfPresent(key T, fn func(value V) (V, bool)) (value V, ok bool) {
+	return m.Compute(key, func(value V, loaded bool) (V, bool) {
+		if !loaded {
+			return value, false
+		}
+
+		return fn(value)
+	})
+}
+
+/*
+LoadOrCompute returns the existing value for the key if present. Otherwise, it stores and returns the result of fn.
+Concurrent callers for the same key wait for a single call of fn, which makes it suitable for expensive constructors.
+The loaded result is true if the value was not computed by this call. If fn panics, the panic is re-raised in every
+waiting caller as a *PanicError.
+
+Example
+
+	asyncMap := NewAsyncMap[string, *Client]()
+	client, loaded := asyncMap.LoadOrCompute("eu", func() *Client {
+		return dial("eu")
+	})
+*/
+func (m *AsyncMap[T, V]) LoadOrCompute(key T, fn func() V) (value V, loaded bool) {
+	if v, ok := m.item.Load(key); ok {
+		return *v.(*V), true //nolint:errcheck
+	}
+
+	computed := false
+	value, _, _ = m.flight.Do(context.Background(), key, func(context.Context) (V, error) {
+		if v, ok := m.item.Load(key); ok {
+			return *v.(*V), nil //nolint:errcheck
+		}
+
+		v, loaded := m.LoadOrStore(key, fn())
+		computed = !loaded
+
+		return v, nil
+	})
+
+	return value, !computed
+}
+
+// commit applies the outcome of a compute function if the entry for key is still the one it was computed from.
+func (m *AsyncMap[T, V]) commit(key T, previous any, loaded bool, value V, keep bool) bool {
+	watched := m.beginWrite()
+	defer m.endWrite(watched)
+
+	var committed bool
+
+	switch {
+	case !loaded && !keep:
+		return true
+	case !loaded:
+		_, loadedNow := m.item.LoadOrStore(key, &value)
+		committed = !loadedNow
+	case keep:
+		committed = m.item.CompareAndSwap(key, previous, &value)
+	default:
+		committed = m.item.CompareAndDelete(key, previous)
+		if committed && watched {
+			m.publishDelete(key, previous)
+		}
+
+		return committed
+	}
+
+	if committed && watched {
+		m.publishPut(key, previous, loaded, value)
+	}
+
+	return committed
+}
+
+// beginWrite blocks snapshots if they are consistent and, while watchers exist, serialises writes. It reports whether
+// events must be published.
+func (m *AsyncMap[T, V]) beginWrite() (watched bool) {
+	if m.consistent {
+		m.snapshot.RLock()
+	}
+
+	if m.hub.count.Load() == 0 {
+		return false
+	}
+
+	m.hub.mutex.Lock()
+
+	return true
+}
+
+func (m *AsyncMap[T, V]) endWrite(watched bool) {
+	if watched {
+		m.hub.mutex.Unlock()
+	}
+
+	if m.consistent {
+		m.snapshot.RUnlock()
+	}
+}
+
+// publishPut publishes the storing of value for key. previous is the *V stored before, if loaded.
+func (m *AsyncMap[T, V]) publishPut(key T, previous any, loaded bool, value V) {
+	event := Event[T, V]{Key: key, New: value, Kind: EventPut, Loaded: loaded}
+	if loaded {
+		event.Old = *previous.(*V) //nolint:errcheck
+	}
+
+	m.hub.publish(event)
+}
+
+// publishDelete publishes the deletion of key. previous is the *V that was stored.
+func (m *AsyncMap[T, V]) publishDelete(key T, previous any) {
+	m.hub.publish(Event[T, V]{Key: key, Old: *previous.(*V), Kind: EventDelete, Loaded: true}) //nolint:errcheck
 }
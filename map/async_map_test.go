@@ -1,7 +1,11 @@
 package m
 
 import (
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -82,4 +86,176 @@ func TestAsyncMap(t *testing.T) {
 		assert.False(t, ok)
 		assert.Equal(t, 0, v)
 	})
+
+	t.Run("Swap", func(t *testing.T) {
+		m := NewAsyncMap[string, int]()
+		v, loaded := m.Swap("a", 1)
+
+		assert.False(t, loaded)
+		assert.Equal(t, 0, v)
+
+		v, loaded = m.Swap("a", 2)
+
+		assert.True(t, loaded)
+		assert.Equal(t, 1, v)
+
+		v, _ = m.Load("a")
+		assert.Equal(t, 2, v)
+	})
+
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		m := NewAsyncMap[string, int]()
+		m.Store("a", 1)
+
+		assert.False(t, CompareAndSwap(m, "a", 2, 3))
+		assert.False(t, CompareAndSwap(m, "b", 1, 3))
+		assert.True(t, CompareAndSwap(m, "a", 1, 3))
+
+		v, _ := m.Load("a")
+		assert.Equal(t, 3, v)
+	})
+
+	t.Run("CompareAndDelete", func(t *testing.T) {
+		m := NewAsyncMap[string, int]()
+		m.Store("a", 1)
+
+		assert.False(t, CompareAndDelete(m, "a", 2))
+		assert.True(t, CompareAndDelete(m, "a", 1))
+
+		_, ok := m.Load("a")
+		assert.False(t, ok)
+	})
+
+	t.Run("Clear/Len", func(t *testing.T) {
+		m := NewAsyncMap[string, int]()
+		m.Store("a", 1)
+		m.Store("b", 2)
+
+		assert.Equal(t, 2, m.Len())
+
+		m.Clear()
+		assert.Equal(t, 0, m.Len())
+	})
+
+	t.Run("Keys/Values", func(t *testing.T) {
+		m := NewAsyncMap[string, int]()
+		m.Store("a", 1)
+		m.Store("b", 2)
+
+		assert.ElementsMatch(t, []string{"a", "b"}, m.Keys())
+		assert.ElementsMatch(t, []int{1, 2}, m.Values())
+	})
+
+	t.Run("Compute", func(t *testing.T) {
+		m := NewAsyncMap[string, int]()
+		increment := func(value int, _ bool) (int, bool) {
+			return value + 1, true
+		}
+
+		v, ok := m.Compute("a", increment)
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+
+		v, ok = m.Compute("a", increment)
+		assert.True(t, ok)
+		assert.Equal(t, 2, v)
+
+		_, ok = m.Compute("a", func(int, bool) (int, bool) { return 0, false })
+		assert.False(t, ok)
+		assert.Equal(t, 0, m.Len())
+
+		_, ok = m.Compute("b", func(int, bool) (int, bool) { return 0, false })
+		assert.False(t, ok)
+		assert.Equal(t, 0, m.Len())
+	})
+
+	t.Run("Compute is atomic", func(t *testing.T) {
+		m := NewAsyncMap[string, []int]()
+		var wg sync.WaitGroup
+
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.Compute("a", func(value []int, _ bool) ([]int, bool) {
+					return append(slices.Clone(value), i), true
+				})
+			}()
+		}
+		wg.Wait()
+
+		v, _ := m.Load("a")
+		assert.Len(t, v, 100)
+	})
+
+	t.Run("ComputeIfAbsent", func(t *testing.T) {
+		m := NewAsyncMap[string, []int]()
+		v, loaded := m.ComputeIfAbsent("a", func() ([]int, bool) { return []int{1}, true })
+
+		assert.False(t, loaded)
+		assert.Equal(t, []int{1}, v)
+
+		v, loaded = m.ComputeIfAbsent("a", func() ([]int, bool) { return []int{2}, true })
+
+		assert.True(t, loaded)
+		assert.Equal(t, []int{1}, v)
+
+		v, loaded = m.ComputeIfAbsent("b", func() ([]int, bool) { return []int{2}, false })
+
+		assert.False(t, loaded)
+		assert.Nil(t, v)
+		assert.Equal(t, 1, m.Len())
+	})
+
+	t.Run("ComputeIfPresent", func(t *testing.T) {
+		m := NewAsyncMap[string, []int]()
+		appendTwo := func(value []int) ([]int, bool) {
+			return append(slices.Clone(value), 2), true
+		}
+
+		_, ok := m.ComputeIfPresent("a", appendTwo)
+		assert.False(t, ok)
+		assert.Equal(t, 0, m.Len())
+
+		m.Store("a", []int{1})
+		v, ok := m.ComputeIfPresent("a", appendTwo)
+		assert.True(t, ok)
+		assert.Equal(t, []int{1, 2}, v)
+
+		_, ok = m.ComputeIfPresent("a", func([]int) ([]int, bool) { return nil, false })
+		assert.False(t, ok)
+		assert.Equal(t, 0, m.Len())
+	})
+
+	t.Run("LoadOrCompute", func(t *testing.T) {
+		m := NewAsyncMap[string, int]()
+		var calls atomic.Int32
+		var wg sync.WaitGroup
+		loadedCount := atomic.Int32{}
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, loaded := m.LoadOrCompute("a", func() int {
+					calls.Add(1)
+					time.Sleep(10 * time.Millisecond)
+
+					return 42
+				})
+				assert.Equal(t, 42, v)
+				if loaded {
+					loadedCount.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, int32(49), loadedCount.Load())
+
+		v, loaded := m.LoadOrCompute("a", func() int { return 0 })
+		assert.True(t, loaded)
+		assert.Equal(t, 42, v)
+	})
 }
@@ -0,0 +1,252 @@
+package m
+
+import (
+	"errors"
+	"iter"
+	"sync"
+)
+
+// ErrDuplicateValue is returned by BiMap.Put when the value is already bound to another key.
+var ErrDuplicateValue = errors.New("value is already bound to another key")
+
+/*
+BiMap is a map whose values are unique, so that keys can be looked up by value. It is not threadsafe; use SyncBiMap
+for concurrent access.
+
+Example
+
+	codes := m.NewBiMap[string, int]()
+	codes.Put("ok", 200)
+	codes.Get("ok")       // 200, true
+	codes.GetKey(200)     // "ok", true
+	codes.Put("ok2", 200) // ErrDuplicateValue
+*/
+type BiMap[K, V comparable] struct {
+	forward map[K]V
+	inverse map[V]K
+}
+
+// NewBiMap returns a new pointer to a BiMap.
+func NewBiMap[K, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{forward: make(map[K]V), inverse: make(map[V]K)}
+}
+
+/*
+Put binds a key to a value, replacing the previous value of the key. It returns ErrDuplicateValue if the value is
+already bound to another key; use ForcePut to move the value instead.
+
+Example
+
+	codes.Put("ok", 200)
+*/
+func (m *BiMap[K, V]) Put(key K, value V) error {
+	if k, ok := m.inverse[value]; ok && k != key {
+		return ErrDuplicateValue
+	}
+
+	m.ForcePut(key, value)
+
+	return nil
+}
+
+/*
+ForcePut binds a key to a value, removing any previous binding of the key and of the value.
+
+Example
+
+	codes.Put("ok", 200)
+	codes.ForcePut("success", 200)
+	codes.Get("ok") // 0, false
+*/
+func (m *BiMap[K, V]) ForcePut(key K, value V) {
+	if v, ok := m.forward[key]; ok {
+		delete(m.inverse, v)
+	}
+
+	if k, ok := m.inverse[value]; ok {
+		delete(m.forward, k)
+	}
+
+	m.forward[key] = value
+	m.inverse[value] = key
+}
+
+/*
+Get returns the value of a key.
+
+Example
+
+	codes.Get("ok") // 200, true
+*/
+func (m *BiMap[K, V]) Get(key K) (value V, ok bool) {
+	value, ok = m.forward[key]
+
+	return value, ok
+}
+
+/*
+GetKey returns the key of a value.
+
+Example
+
+	codes.GetKey(200) // "ok", true
+*/
+func (m *BiMap[K, V]) GetKey(value V) (key K, ok bool) {
+	key, ok = m.inverse[value]
+
+	return key, ok
+}
+
+/*
+Delete deletes a key and its value, returning the value if any.
+
+Example
+
+	codes.Delete("ok") // 200, true
+*/
+func (m *BiMap[K, V]) Delete(key K) (value V, ok bool) {
+	value, ok = m.forward[key]
+	if ok {
+		delete(m.forward, key)
+		delete(m.inverse, value)
+	}
+
+	return value, ok
+}
+
+/*
+DeleteValue deletes a value and its key, returning the key if any.
+
+Example
+
+	codes.DeleteValue(200) // "ok", true
+*/
+func (m *BiMap[K, V]) DeleteValue(value V) (key K, ok bool) {
+	key, ok = m.inverse[value]
+	if ok {
+		delete(m.inverse, value)
+		delete(m.forward, key)
+	}
+
+	return key, ok
+}
+
+/*
+Inverse returns a view of the map from values to keys. Both maps share their entries, so changes to one are visible
+in the other.
+
+Example
+
+	names := codes.Inverse()
+	names.Get(200) // "ok", true
+*/
+func (m *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return &BiMap[V, K]{forward: m.inverse, inverse: m.forward}
+}
+
+// Len returns the number of entries.
+func (m *BiMap[K, V]) Len() int {
+	return len(m.forward)
+}
+
+/*
+All returns an iterator over the keys and values.
+
+Example
+
+	for key, value := range codes.All() {
+		fmt.Printf("%s: %d", key, value)
+	}
+*/
+func (m *BiMap[K, V]) All() iter.Seq2[K, V] {
+	return All(m.forward)
+}
+
+/*
+SyncBiMap is a threadsafe BiMap.
+
+Example
+
+	codes := m.NewSyncBiMap[string, int]()
+	err := codes.Put("ok", 200)
+*/
+type SyncBiMap[K, V comparable] struct {
+	items *BiMap[K, V]
+	mutex sync.RWMutex
+}
+
+// NewSyncBiMap returns a new pointer to a SyncBiMap.
+func NewSyncBiMap[K, V comparable]() *SyncBiMap[K, V] {
+	return &SyncBiMap[K, V]{items: NewBiMap[K, V]()}
+}
+
+// Put binds a key to a value. It returns ErrDuplicateValue if the value is already bound to another key.
+func (m *SyncBiMap[K, V]) Put(key K, value V) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.items.Put(key, value)
+}
+
+// ForcePut binds a key to a value, removing any previous binding of the key and of the value.
+func (m *SyncBiMap[K, V]) ForcePut(key K, value V) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.items.ForcePut(key, value)
+}
+
+// Get returns the value of a key.
+func (m *SyncBiMap[K, V]) Get(key K) (value V, ok bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.items.Get(key)
+}
+
+// GetKey returns the key of a value.
+func (m *SyncBiMap[K, V]) GetKey(value V) (key K, ok bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.items.GetKey(value)
+}
+
+// Delete deletes a key and its value, returning the value if any.
+func (m *SyncBiMap[K, V]) Delete(key K) (value V, ok bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.items.Delete(key)
+}
+
+// DeleteValue deletes a value and its key, returning the key if any.
+func (m *SyncBiMap[K, V]) DeleteValue(value V) (key K, ok bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.items.DeleteValue(value)
+}
+
+// Len returns the number of entries.
+func (m *SyncBiMap[K, V]) Len() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.items.Len()
+}
+
+// All returns an iterator over a snapshot of the keys and values, so the loop body may modify the map.
+func (m *SyncBiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.mutex.RLock()
+		entries := Entries(m.items.forward)
+		m.mutex.RUnlock()
+
+		for _, entry := range entries {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package m
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBiMap(t *testing.T) {
+	t.Run("forward and inverse lookups", func(t *testing.T) {
+		bm := NewBiMap[string, int]()
+		assert.NoError(t, bm.Put("ok", 200))
+		assert.NoError(t, bm.Put("created", 201))
+
+		v, ok := bm.Get("ok")
+		assert.True(t, ok)
+		assert.Equal(t, 200, v)
+
+		k, ok := bm.GetKey(201)
+		assert.True(t, ok)
+		assert.Equal(t, "created", k)
+
+		_, ok = bm.GetKey(404)
+		assert.False(t, ok)
+		assert.Equal(t, 2, bm.Len())
+	})
+
+	t.Run("Put enforces unique values", func(t *testing.T) {
+		bm := NewBiMap[string, int]()
+		assert.NoError(t, bm.Put("ok", 200))
+		assert.ErrorIs(t, bm.Put("success", 200), ErrDuplicateValue)
+		assert.NoError(t, bm.Put("ok", 200))
+
+		k, _ := bm.GetKey(200)
+		assert.Equal(t, "ok", k)
+	})
+
+	t.Run("Put replaces the value of a key", func(t *testing.T) {
+		bm := NewBiMap[string, int]()
+		assert.NoError(t, bm.Put("ok", 200))
+		assert.NoError(t, bm.Put("ok", 204))
+
+		_, ok := bm.GetKey(200)
+		assert.False(t, ok)
+		assert.Equal(t, map[string]int{"ok": 204}, Collect(bm.All()))
+	})
+
+	t.Run("ForcePut moves the value", func(t *testing.T) {
+		bm := NewBiMap[string, int]()
+		assert.NoError(t, bm.Put("ok", 200))
+		assert.NoError(t, bm.Put("success", 201))
+		bm.ForcePut("success", 200)
+
+		_, ok := bm.Get("ok")
+		assert.False(t, ok)
+		_, ok = bm.GetKey(201)
+		assert.False(t, ok)
+		assert.Equal(t, map[string]int{"success": 200}, Collect(bm.All()))
+		assert.Equal(t, map[int]string{200: "success"}, Collect(bm.Inverse().All()))
+	})
+
+	t.Run("Delete and DeleteValue", func(t *testing.T) {
+		bm := NewBiMap[string, int]()
+		bm.ForcePut("ok", 200)
+		bm.ForcePut("created", 201)
+
+		v, ok := bm.Delete("ok")
+		assert.True(t, ok)
+		assert.Equal(t, 200, v)
+		_, ok = bm.Delete("ok")
+		assert.False(t, ok)
+
+		k, ok := bm.DeleteValue(201)
+		assert.True(t, ok)
+		assert.Equal(t, "created", k)
+		_, ok = bm.DeleteValue(201)
+		assert.False(t, ok)
+
+		assert.Equal(t, 0, bm.Len())
+	})
+
+	t.Run("Inverse shares the entries", func(t *testing.T) {
+		bm := NewBiMap[string, int]()
+		inverse := bm.Inverse()
+		assert.NoError(t, inverse.Put(200, "ok"))
+
+		v, ok := bm.Get("ok")
+		assert.True(t, ok)
+		assert.Equal(t, 200, v)
+		assert.ErrorIs(t, bm.Put("success", 200), ErrDuplicateValue)
+	})
+}
+
+func TestSyncBiMap(t *testing.T) {
+	t.Run("concurrent puts keep values unique", func(t *testing.T) {
+		bm := NewSyncBiMap[int, string]()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				errs <- bm.Put(i, "leader")
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		failed := 0
+		for err := range errs {
+			if err != nil {
+				assert.ErrorIs(t, err, ErrDuplicateValue)
+				failed++
+			}
+		}
+
+		assert.Equal(t, 9, failed)
+		assert.Equal(t, 1, bm.Len())
+	})
+
+	t.Run("methods", func(t *testing.T) {
+		bm := NewSyncBiMap[string, int]()
+		bm.ForcePut("ok", 200)
+
+		v, _ := bm.Get("ok")
+		assert.Equal(t, 200, v)
+		k, _ := bm.GetKey(200)
+		assert.Equal(t, "ok", k)
+
+		for key, value := range bm.All() {
+			bm.ForcePut(key+"!", value)
+		}
+
+		assert.Equal(t, map[string]int{"ok!": 200}, Collect(bm.All()))
+
+		_, ok := bm.Delete("ok!")
+		assert.True(t, ok)
+		bm.ForcePut("ok", 200)
+		_, ok = bm.DeleteValue(200)
+		assert.True(t, ok)
+		assert.Equal(t, 0, bm.Len())
+	})
+}
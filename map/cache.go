@@ -0,0 +1,252 @@
+package m
+
+import (
+	"sync"
+)
+
+/*
+CacheOptions configures a Cache.
+
+Example
+
+	opts := m.CacheOptions[string, []byte]{
+		Capacity: 64 << 20,
+		Policy:   m.TwoQ,
+		Size:     func(key string, value []byte) int64 { return int64(len(key) + len(value)) },
+	}
+*/
+type CacheOptions[K comparable, V any] struct {
+	// Size returns the cost of an entry. Defaults to 1 per entry, which makes Capacity a number of entries.
+	Size func(key K, value V) int64
+	// OnEvict is called for every entry evicted to make room. It is not called for entries removed with Delete.
+	OnEvict func(key K, value V)
+	// Capacity is the maximum total size of the entries.
+	Capacity int64
+	// Policy decides which entry is evicted when the cache is full. Defaults to LRU.
+	Policy EvictionPolicy
+}
+
+// CacheStats holds the statistics of a Cache.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// HitRatio returns the share of lookups that were hits, or 0 if there were no lookups.
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(total)
+}
+
+type cacheEntry[V any] struct {
+	value V
+	size  int64
+}
+
+/*
+Cache is a generic threadsafe map bounded by a capacity. When it is full, entries are evicted according to its
+eviction policy.
+
+Example
+
+	cache := m.NewCache(m.CacheOptions[string, int]{Capacity: 2})
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a")    // 1, true
+	cache.Set("c", 3) // evicts "b", the least recently used entry
+*/
+type Cache[K comparable, V any] struct {
+	policy policy[K]
+	items  map[K]cacheEntry[V]
+	opts   CacheOptions[K, V]
+	stats  CacheStats
+	size   int64
+	mutex  sync.Mutex
+}
+
+// NewCache returns a new pointer to a Cache.
+func NewCache[K comparable, V any](opts CacheOptions[K, V]) *Cache[K, V] {
+	if opts.Capacity < 1 {
+		panic("capacity must be greater than or equal to 1")
+	}
+
+	if opts.Size == nil {
+		opts.Size = func(K, V) int64 { return 1 }
+	}
+
+	return &Cache[K, V]{policy: newPolicy[K](opts.Policy), items: make(map[K]cacheEntry[V]), opts: opts}
+}
+
+/*
+Get returns the value and true if the key exists in the cache, otherwise it returns the zero value and false.
+It counts as a use of the entry and is recorded as a hit or a miss.
+
+Example
+
+	cache.Set("a", 1)
+	cache.Get("a") // 1, true
+	cache.Get("b") // 0, false
+*/
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+
+		return value, false
+	}
+
+	c.stats.Hits++
+	c.policy.touch(key)
+
+	return entry.value, true
+}
+
+/*
+Peek returns the value like Get, without counting as a use of the entry or updating the statistics.
+
+Example
+
+	cache.Set("a", 1)
+	cache.Peek("a") // 1, true
+*/
+func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.items[key]
+
+	return entry.value, ok
+}
+
+/*
+Set sets the value for a key and evicts entries until the cache fits its capacity. An entry larger than the whole
+capacity is evicted right away, along with the entry it replaces.
+
+Example
+
+	cache.Set("a", 1)
+*/
+func (c *Cache[K, V]) Set(key K, value V) {
+	size := c.opts.Size(key, value)
+
+	c.mutex.Lock()
+
+	var evicted []Entry[K, V]
+
+	old, exists := c.items[key]
+
+	switch {
+	case size > c.opts.Capacity:
+		if exists {
+			delete(c.items, key)
+			c.size -= old.size
+			c.policy.remove(key)
+			c.stats.Evictions++
+			evicted = append(evicted, Entry[K, V]{Key: key, Value: old.value})
+		}
+
+		c.stats.Evictions++
+		evicted = append(evicted, Entry[K, V]{Key: key, Value: value})
+	case exists:
+		c.items[key] = cacheEntry[V]{value: value, size: size}
+		c.size += size - old.size
+		c.policy.touch(key)
+		evicted = c.evict()
+	default:
+		c.items[key] = cacheEntry[V]{value: value, size: size}
+		c.size += size
+		c.policy.insert(key)
+		evicted = c.evict()
+	}
+
+	c.mutex.Unlock()
+
+	if c.opts.OnEvict != nil {
+		for _, entry := range evicted {
+			c.opts.OnEvict(entry.Key, entry.Value)
+		}
+	}
+}
+
+/*
+Delete deletes the value for a key and returns true if it was present.
+
+Example
+
+	cache.Set("a", 1)
+	cache.Delete("a") // true
+*/
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.items[key]
+	if !ok {
+		return false
+	}
+
+	delete(c.items, key)
+	c.size -= entry.size
+	c.policy.remove(key)
+
+	return true
+}
+
+// Len returns the number of entries in the cache.
+func (c *Cache[K, V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.items)
+}
+
+// Size returns the total size of the entries in the cache.
+func (c *Cache[K, V]) Size() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.size
+}
+
+/*
+Stats returns a copy of the hit, miss and eviction counters.
+
+Example
+
+	stats := cache.Stats()
+	fmt.Printf("hit ratio %.2f", stats.HitRatio())
+*/
+func (c *Cache[K, V]) Stats() CacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.stats
+}
+
+// evict removes entries until the cache fits its capacity. It must be called with the mutex held.
+func (c *Cache[K, V]) evict() []Entry[K, V] {
+	var evicted []Entry[K, V]
+
+	for c.size > c.opts.Capacity {
+		key, ok := c.policy.victim()
+		if !ok {
+			break
+		}
+
+		entry := c.items[key]
+		delete(c.items, key)
+		c.size -= entry.size
+		c.stats.Evictions++
+		evicted = append(evicted, Entry[K, V]{Key: key, Value: entry.value})
+	}
+
+	return evicted
+}
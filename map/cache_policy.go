@@ -0,0 +1,274 @@
+package m
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+// EvictionPolicy decides which entry a Cache evicts when it is full.
+type EvictionPolicy int
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently used entry, and the least recently used one among equally frequent entries.
+	LFU
+	// TwoQ is the scan-resistant 2Q policy: new entries go to a FIFO queue and are only promoted to the main LRU
+	// queue when they are accessed again after having been evicted from it, so one-off scans do not flush hot entries.
+	TwoQ
+)
+
+func (p EvictionPolicy) String() string {
+	switch p {
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	case TwoQ:
+		return "2Q"
+	default:
+		return "unknown"
+	}
+}
+
+// policy tracks the keys of a Cache. victim removes and returns the next key to evict.
+type policy[K comparable] interface {
+	insert(key K)
+	touch(key K)
+	remove(key K)
+	victim() (K, bool)
+}
+
+func newPolicy[K comparable](p EvictionPolicy) policy[K] {
+	switch p {
+	case LRU:
+		return &lruPolicy[K]{keys: newKeyList[K]()}
+	case LFU:
+		return &lfuPolicy[K]{index: make(map[K]*lfuItem[K])}
+	case TwoQ:
+		return &twoQPolicy[K]{in: newKeyList[K](), out: newKeyList[K](), main: newKeyList[K]()}
+	default:
+		panic("unknown eviction policy")
+	}
+}
+
+// keyList is a list of unique keys, most recent at the front.
+type keyList[K comparable] struct {
+	elements map[K]*list.Element
+	order    *list.List
+}
+
+func newKeyList[K comparable]() *keyList[K] {
+	return &keyList[K]{elements: make(map[K]*list.Element), order: list.New()}
+}
+
+func (l *keyList[K]) has(key K) bool {
+	_, ok := l.elements[key]
+
+	return ok
+}
+
+func (l *keyList[K]) pushFront(key K) {
+	l.elements[key] = l.order.PushFront(key)
+}
+
+func (l *keyList[K]) moveToFront(key K) {
+	if e, ok := l.elements[key]; ok {
+		l.order.MoveToFront(e)
+	}
+}
+
+func (l *keyList[K]) remove(key K) bool {
+	e, ok := l.elements[key]
+	if !ok {
+		return false
+	}
+
+	l.order.Remove(e)
+	delete(l.elements, key)
+
+	return true
+}
+
+func (l *keyList[K]) popBack() (K, bool) {
+	e := l.order.Back()
+	if e == nil {
+		var zero K
+
+		return zero, false
+	}
+
+	key := e.Value.(K) //nolint:errcheck
+	l.order.Remove(e)
+	delete(l.elements, key)
+
+	return key, true
+}
+
+func (l *keyList[K]) len() int {
+	return len(l.elements)
+}
+
+type lruPolicy[K comparable] struct {
+	keys *keyList[K]
+}
+
+func (p *lruPolicy[K]) insert(key K) {
+	p.keys.pushFront(key)
+}
+
+func (p *lruPolicy[K]) touch(key K) {
+	p.keys.moveToFront(key)
+}
+
+func (p *lruPolicy[K]) remove(key K) {
+	p.keys.remove(key)
+}
+
+func (p *lruPolicy[K]) victim() (K, bool) {
+	return p.keys.popBack()
+}
+
+type lfuItem[K comparable] struct {
+	key   K
+	freq  uint64
+	tick  uint64
+	index int
+}
+
+// lfuHeap is a min-heap of keys ordered by frequency and then by last access.
+type lfuHeap[K comparable] []*lfuItem[K]
+
+func (h lfuHeap[K]) Len() int {
+	return len(h)
+}
+
+func (h lfuHeap[K]) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+
+	return h[i].tick < h[j].tick
+}
+
+func (h lfuHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap[K]) Push(x any) {
+	item := x.(*lfuItem[K]) //nolint:errcheck
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *lfuHeap[K]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+
+	return item
+}
+
+type lfuPolicy[K comparable] struct {
+	index map[K]*lfuItem[K]
+	items lfuHeap[K]
+	tick  uint64
+}
+
+func (p *lfuPolicy[K]) insert(key K) {
+	p.tick++
+	item := &lfuItem[K]{key: key, freq: 1, tick: p.tick}
+	p.index[key] = item
+	heap.Push(&p.items, item)
+}
+
+func (p *lfuPolicy[K]) touch(key K) {
+	item, ok := p.index[key]
+	if !ok {
+		return
+	}
+
+	p.tick++
+	item.freq++
+	item.tick = p.tick
+	heap.Fix(&p.items, item.index)
+}
+
+func (p *lfuPolicy[K]) remove(key K) {
+	item, ok := p.index[key]
+	if !ok {
+		return
+	}
+
+	heap.Remove(&p.items, item.index)
+	delete(p.index, key)
+}
+
+func (p *lfuPolicy[K]) victim() (K, bool) {
+	if len(p.items) == 0 {
+		var zero K
+
+		return zero, false
+	}
+
+	item := heap.Pop(&p.items).(*lfuItem[K]) //nolint:errcheck
+	delete(p.index, item.key)
+
+	return item.key, true
+}
+
+// twoQPolicy implements the full 2Q algorithm. in holds keys seen once, out remembers keys recently evicted from in,
+// and main holds keys that were seen again while remembered in out.
+type twoQPolicy[K comparable] struct {
+	in   *keyList[K]
+	out  *keyList[K]
+	main *keyList[K]
+}
+
+const (
+	twoQInRatio  = 4
+	twoQOutRatio = 2
+)
+
+func (p *twoQPolicy[K]) insert(key K) {
+	if p.out.remove(key) {
+		p.main.pushFront(key)
+
+		return
+	}
+
+	p.in.pushFront(key)
+}
+
+func (p *twoQPolicy[K]) touch(key K) {
+	p.main.moveToFront(key)
+}
+
+func (p *twoQPolicy[K]) remove(key K) {
+	if !p.in.remove(key) {
+		p.main.remove(key)
+	}
+}
+
+func (p *twoQPolicy[K]) victim() (K, bool) {
+	total := p.in.len() + p.main.len()
+
+	if p.in.len() > max(1, total/twoQInRatio) || p.main.len() == 0 {
+		key, ok := p.in.popBack()
+		if !ok {
+			return key, false
+		}
+
+		p.out.pushFront(key)
+		for p.out.len() > max(1, total/twoQOutRatio) {
+			p.out.popBack()
+		}
+
+		return key, true
+	}
+
+	return p.main.popBack()
+}
@@ -0,0 +1,189 @@
+package m
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("LRU", func(t *testing.T) {
+		var evicted []string
+		c := NewCache(CacheOptions[string, int]{
+			Capacity: 2,
+			OnEvict:  func(key string, _ int) { evicted = append(evicted, key) },
+		})
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Get("a")
+		c.Set("c", 3)
+
+		assert.Equal(t, []string{"b"}, evicted)
+		assert.Equal(t, 2, c.Len())
+
+		_, ok := c.Peek("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("LFU", func(t *testing.T) {
+		var evicted []string
+		c := NewCache(CacheOptions[string, int]{
+			Capacity: 2,
+			Policy:   LFU,
+			OnEvict:  func(key string, _ int) { evicted = append(evicted, key) },
+		})
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Get("a")
+		c.Set("c", 3)
+		c.Get("c")
+		c.Set("d", 4)
+
+		// b and c are both used once, b less recently; d is used less often than a and c.
+		assert.Equal(t, []string{"b", "d"}, evicted)
+
+		_, ok := c.Peek("a")
+		assert.True(t, ok)
+	})
+
+	t.Run("2Q resists scans", func(t *testing.T) {
+		c := NewCache(CacheOptions[string, int]{Capacity: 8, Policy: TwoQ})
+
+		// "hot" is evicted from the FIFO queue once and promoted to the main queue when it comes back.
+		c.Set("hot", 0)
+		for i := 0; i < 8; i++ {
+			c.Set("warmup"+strconv.Itoa(i), i)
+		}
+		c.Set("hot", 0)
+		c.Get("hot")
+
+		for i := 0; i < 100; i++ {
+			c.Set("scan"+strconv.Itoa(i), i)
+		}
+
+		_, ok := c.Get("hot")
+		assert.True(t, ok)
+		assert.Equal(t, 8, c.Len())
+	})
+
+	t.Run("size function", func(t *testing.T) {
+		c := NewCache(CacheOptions[string, string]{
+			Capacity: 10,
+			Size:     func(_ string, value string) int64 { return int64(len(value)) },
+		})
+		c.Set("a", "12345")
+		c.Set("b", "1234")
+		assert.Equal(t, int64(9), c.Size())
+
+		c.Set("c", "12")
+		assert.Equal(t, int64(6), c.Size())
+		assert.Equal(t, 2, c.Len())
+
+		c.Set("b", "1")
+		assert.Equal(t, int64(3), c.Size())
+	})
+
+	t.Run("entry larger than capacity", func(t *testing.T) {
+		var evicted []string
+		c := NewCache(CacheOptions[string, string]{
+			Capacity: 3,
+			Size:     func(_ string, value string) int64 { return int64(len(value)) },
+			OnEvict:  func(key string, _ string) { evicted = append(evicted, key) },
+		})
+		c.Set("a", "1")
+		c.Set("b", "1234")
+
+		assert.Equal(t, []string{"b"}, evicted)
+		assert.Equal(t, 1, c.Len())
+		assert.Equal(t, int64(1), c.Size())
+	})
+
+	t.Run("overwrite larger than capacity evicts the old entry", func(t *testing.T) {
+		var evicted []string
+		c := NewCache(CacheOptions[string, string]{
+			Capacity: 3,
+			Size:     func(_ string, value string) int64 { return int64(len(value)) },
+			OnEvict:  func(key string, value string) { evicted = append(evicted, key+"="+value) },
+		})
+		c.Set("a", "1")
+		c.Set("a", "1234")
+
+		assert.Equal(t, []string{"a=1", "a=1234"}, evicted)
+		assert.Equal(t, 0, c.Len())
+		assert.Equal(t, int64(0), c.Size())
+		assert.Equal(t, uint64(2), c.Stats().Evictions)
+	})
+
+	t.Run("update keeps the entry", func(t *testing.T) {
+		c := NewCache(CacheOptions[string, int]{Capacity: 2})
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Set("a", 3)
+		c.Set("c", 4)
+
+		v, ok := c.Peek("a")
+		assert.True(t, ok)
+		assert.Equal(t, 3, v)
+
+		_, ok = c.Peek("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		for _, policy := range []EvictionPolicy{LRU, LFU, TwoQ} {
+			c := NewCache(CacheOptions[string, int]{Capacity: 2, Policy: policy})
+			c.Set("a", 1)
+
+			assert.True(t, c.Delete("a"), policy.String())
+			assert.False(t, c.Delete("a"), policy.String())
+			assert.Equal(t, 0, c.Len(), policy.String())
+			assert.Equal(t, int64(0), c.Size(), policy.String())
+
+			c.Set("b", 2)
+			c.Set("c", 3)
+			c.Set("d", 4)
+			assert.Equal(t, 2, c.Len(), policy.String())
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		c := NewCache(CacheOptions[string, int]{Capacity: 1})
+		assert.Equal(t, 0.0, c.Stats().HitRatio())
+
+		c.Set("a", 1)
+		c.Get("a")
+		c.Get("b")
+		c.Get("a")
+		c.Set("b", 2)
+
+		stats := c.Stats()
+		assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Evictions: 1}, stats)
+		assert.InDelta(t, 2.0/3.0, stats.HitRatio(), 0.001)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		c := NewCache(CacheOptions[int, int]{Capacity: 16, Policy: TwoQ})
+		var wg sync.WaitGroup
+
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					c.Set(i%32, i)
+					c.Get((i + g) % 32)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, c.Len(), 16)
+	})
+
+	t.Run("panics on invalid capacity", func(t *testing.T) {
+		assert.Panics(t, func() { NewCache(CacheOptions[string, int]{}) })
+		assert.Panics(t, func() { NewCache(CacheOptions[string, int]{Capacity: 1, Policy: EvictionPolicy(9)}) })
+	})
+}
@@ -0,0 +1,337 @@
+package m
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sync"
+)
+
+type counterEntry struct {
+	count int
+	// seq is the order in which the item was first counted. It breaks ties between equal counts.
+	seq int
+}
+
+/*
+Counter counts occurrences of items. Only positive counts are kept. Items with equal counts are ordered by when they
+were first counted, so results are deterministic. Counter is not threadsafe; use SyncCounter for concurrent access.
+
+Example
+
+	words := m.NewCounter[string]()
+	for _, word := range strings.Fields("a b a c a b") {
+		words.Add(word)
+	}
+	words.MostCommon(2) // []m.Entry[string, int]{{"a", 3}, {"b", 2}}
+*/
+type Counter[T comparable] struct {
+	items map[T]counterEntry
+	seq   int
+	total int
+}
+
+// NewCounter returns a new pointer to a Counter.
+func NewCounter[T comparable]() *Counter[T] {
+	return &Counter[T]{items: make(map[T]counterEntry)}
+}
+
+/*
+CounterFromSlice returns a new pointer to a Counter with the occurrences of the items of the slice.
+
+Example
+
+	c := m.CounterFromSlice([]string{"a", "b", "a"})
+	c.Get("a") // 2
+*/
+func CounterFromSlice[T comparable](items []T) *Counter[T] {
+	c := NewCounter[T]()
+	for _, item := range items {
+		c.Add(item)
+	}
+
+	return c
+}
+
+/*
+Add counts one occurrence of the item.
+
+Example
+
+	c.Add("a")
+*/
+func (c *Counter[T]) Add(item T) {
+	c.AddN(item, 1)
+}
+
+/*
+AddN adds n to the count of the item. n may be negative; the item is removed when its count drops to zero or below.
+
+Example
+
+	c.AddN("a", 3)
+	c.AddN("a", -1)
+	c.Get("a") // 2
+*/
+func (c *Counter[T]) AddN(item T, n int) {
+	entry, ok := c.items[item]
+	if !ok {
+		entry.seq = c.seq
+		c.seq++
+	}
+
+	entry.count += n
+	if entry.count <= 0 {
+		c.total -= entry.count - n
+		delete(c.items, item)
+
+		return
+	}
+
+	c.total += n
+	c.items[item] = entry
+}
+
+/*
+Get returns the count of the item, zero if it was never counted.
+
+Example
+
+	c.Get("a") // 2
+*/
+func (c *Counter[T]) Get(item T) int {
+	return c.items[item].count
+}
+
+// Len returns the number of distinct items.
+func (c *Counter[T]) Len() int {
+	return len(c.items)
+}
+
+// Total returns the sum of all counts.
+func (c *Counter[T]) Total() int {
+	return c.total
+}
+
+/*
+MostCommon returns the n items with the highest counts, from the most to the least common. If n is negative or larger
+than the number of items, all items are returned.
+
+Example
+
+	c := m.CounterFromSlice([]string{"a", "b", "a", "c"})
+	c.MostCommon(1) // []m.Entry[string, int]{{"a", 2}}
+*/
+func (c *Counter[T]) MostCommon(n int) []Entry[T, int] {
+	return c.sorted(n, func(a, b counterEntry) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), cmp.Compare(a.seq, b.seq))
+	})
+}
+
+/*
+LeastCommon returns the n items with the lowest counts, from the least to the most common. If n is negative or larger
+than the number of items, all items are returned.
+
+Example
+
+	c := m.CounterFromSlice([]string{"a", "b", "a", "c"})
+	c.LeastCommon(2) // []m.Entry[string, int]{{"b", 1}, {"c", 1}}
+*/
+func (c *Counter[T]) LeastCommon(n int) []Entry[T, int] {
+	return c.sorted(n, func(a, b counterEntry) int {
+		return cmp.Or(cmp.Compare(a.count, b.count), cmp.Compare(a.seq, b.seq))
+	})
+}
+
+/*
+All returns an iterator over the items and their counts in the order they were first counted.
+
+Example
+
+	for item, count := range c.All() {
+		fmt.Printf("%s: %d", item, count)
+	}
+*/
+func (c *Counter[T]) All() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		for _, entry := range c.sorted(-1, func(a, b counterEntry) int { return cmp.Compare(a.seq, b.seq) }) {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+/*
+Plus returns a new Counter with the counts of both counters added.
+
+Example
+
+	a := m.CounterFromSlice([]string{"a", "b"})
+	b := m.CounterFromSlice([]string{"a"})
+	a.Plus(b).Get("a") // 2
+*/
+func (c *Counter[T]) Plus(other *Counter[T]) *Counter[T] {
+	result := c.Clone()
+	for item, count := range other.All() {
+		result.AddN(item, count)
+	}
+
+	return result
+}
+
+/*
+Minus returns a new Counter with the counts of other subtracted. Items whose count drops to zero or below are removed.
+
+Example
+
+	a := m.CounterFromSlice([]string{"a", "a", "b"})
+	b := m.CounterFromSlice([]string{"a", "b"})
+	a.Minus(b).MostCommon(-1) // []m.Entry[string, int]{{"a", 1}}
+*/
+func (c *Counter[T]) Minus(other *Counter[T]) *Counter[T] {
+	result := c.Clone()
+	for item, count := range other.All() {
+		if _, ok := result.items[item]; ok {
+			result.AddN(item, -count)
+		}
+	}
+
+	return result
+}
+
+/*
+Intersect returns a new Counter with the items of both counters and the minimum of their counts.
+
+Example
+
+	a := m.CounterFromSlice([]string{"a", "a", "b"})
+	b := m.CounterFromSlice([]string{"a", "c"})
+	a.Intersect(b).MostCommon(-1) // []m.Entry[string, int]{{"a", 1}}
+*/
+func (c *Counter[T]) Intersect(other *Counter[T]) *Counter[T] {
+	result := NewCounter[T]()
+	for item, count := range c.All() {
+		if n := min(count, other.Get(item)); n > 0 {
+			result.AddN(item, n)
+		}
+	}
+
+	return result
+}
+
+// Clone returns a copy of the counter.
+func (c *Counter[T]) Clone() *Counter[T] {
+	result := NewCounter[T]()
+	for item, count := range c.All() {
+		result.AddN(item, count)
+	}
+
+	return result
+}
+
+func (c *Counter[T]) sorted(n int, compare func(a, b counterEntry) int) []Entry[T, int] {
+	items := Entries(c.items)
+	slices.SortFunc(items, func(a, b Entry[T, counterEntry]) int {
+		return compare(a.Value, b.Value)
+	})
+
+	if n >= 0 && n < len(items) {
+		items = items[:n]
+	}
+
+	result := make([]Entry[T, int], len(items))
+	for i, entry := range items {
+		result[i] = Entry[T, int]{Key: entry.Key, Value: entry.Value.count}
+	}
+
+	return result
+}
+
+/*
+SyncCounter is a threadsafe Counter, e.g. for counting from the workers of a result.Group.
+
+Example
+
+	words := m.NewSyncCounter[string]()
+	group.Go(func() ([]int, error) {
+		words.Add("a")
+		return nil, nil
+	})
+*/
+type SyncCounter[T comparable] struct {
+	items *Counter[T]
+	mutex sync.RWMutex
+}
+
+// NewSyncCounter returns a new pointer to a SyncCounter.
+func NewSyncCounter[T comparable]() *SyncCounter[T] {
+	return &SyncCounter[T]{items: NewCounter[T]()}
+}
+
+// Add counts one occurrence of the item.
+func (c *SyncCounter[T]) Add(item T) {
+	c.AddN(item, 1)
+}
+
+// AddN adds n to the count of the item. The item is removed when its count drops to zero or below.
+func (c *SyncCounter[T]) AddN(item T, n int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.items.AddN(item, n)
+}
+
+// Get returns the count of the item, zero if it was never counted.
+func (c *SyncCounter[T]) Get(item T) int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.items.Get(item)
+}
+
+// Len returns the number of distinct items.
+func (c *SyncCounter[T]) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.items.Len()
+}
+
+// Total returns the sum of all counts.
+func (c *SyncCounter[T]) Total() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.items.Total()
+}
+
+// MostCommon returns the n items with the highest counts, from the most to the least common.
+func (c *SyncCounter[T]) MostCommon(n int) []Entry[T, int] {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.items.MostCommon(n)
+}
+
+// LeastCommon returns the n items with the lowest counts, from the least to the most common.
+func (c *SyncCounter[T]) LeastCommon(n int) []Entry[T, int] {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.items.LeastCommon(n)
+}
+
+/*
+Snapshot returns a copy of the counts as a Counter, e.g. to combine them with Plus, Minus or Intersect.
+
+Example
+
+	total := words.Snapshot().Plus(otherWords.Snapshot())
+*/
+func (c *SyncCounter[T]) Snapshot() *Counter[T] {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.items.Clone()
+}
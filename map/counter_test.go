@@ -0,0 +1,119 @@
+package m
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/neurocode-io/go-pkgs/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	t.Run("Add, AddN and Get", func(t *testing.T) {
+		c := NewCounter[string]()
+		c.Add("a")
+		c.Add("a")
+		c.AddN("b", 3)
+
+		assert.Equal(t, 2, c.Get("a"))
+		assert.Equal(t, 3, c.Get("b"))
+		assert.Equal(t, 0, c.Get("c"))
+		assert.Equal(t, 2, c.Len())
+		assert.Equal(t, 5, c.Total())
+	})
+
+	t.Run("non-positive counts are removed", func(t *testing.T) {
+		c := CounterFromSlice([]string{"a", "a", "b"})
+		c.AddN("a", -1)
+		assert.Equal(t, 1, c.Get("a"))
+
+		c.AddN("a", -5)
+		c.AddN("c", -1)
+		c.AddN("d", 0)
+
+		assert.Equal(t, 0, c.Get("a"))
+		assert.Equal(t, 1, c.Len())
+		assert.Equal(t, 1, c.Total())
+	})
+
+	t.Run("MostCommon and LeastCommon break ties by first occurrence", func(t *testing.T) {
+		c := CounterFromSlice(strings.Fields("d b a c a b a"))
+
+		assert.Equal(t, []Entry[string, int]{{"a", 3}, {"b", 2}}, c.MostCommon(2))
+		assert.Equal(t, []Entry[string, int]{{"a", 3}, {"b", 2}, {"d", 1}, {"c", 1}}, c.MostCommon(-1))
+		assert.Equal(t, []Entry[string, int]{{"d", 1}, {"c", 1}, {"b", 2}}, c.LeastCommon(3))
+		assert.Len(t, c.LeastCommon(10), 4)
+		assert.Empty(t, c.MostCommon(0))
+	})
+
+	t.Run("All iterates in first occurrence order", func(t *testing.T) {
+		c := CounterFromSlice(strings.Fields("b a b c"))
+
+		assert.Equal(t, []Entry[string, int]{{"b", 2}, {"a", 1}, {"c", 1}}, CollectEntries(c.All()))
+
+		for range c.All() {
+			break
+		}
+	})
+
+	t.Run("Plus, Minus and Intersect", func(t *testing.T) {
+		a := CounterFromSlice([]string{"a", "a", "b", "c"})
+		b := CounterFromSlice([]string{"a", "b", "b", "d"})
+
+		assert.Equal(t, []Entry[string, int]{{"a", 3}, {"b", 3}, {"c", 1}, {"d", 1}}, a.Plus(b).MostCommon(-1))
+		assert.Equal(t, []Entry[string, int]{{"a", 1}, {"c", 1}}, a.Minus(b).MostCommon(-1))
+		assert.Equal(t, []Entry[string, int]{{"a", 1}, {"b", 1}}, a.Intersect(b).MostCommon(-1))
+
+		assert.Equal(t, 4, a.Total(), "operands are not modified")
+		assert.Equal(t, 4, b.Total(), "operands are not modified")
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		c := CounterFromSlice([]string{"a"})
+		clone := c.Clone()
+		clone.Add("a")
+
+		assert.Equal(t, 1, c.Get("a"))
+		assert.Equal(t, 2, clone.Get("a"))
+	})
+}
+
+func TestSyncCounter(t *testing.T) {
+	t.Run("counts from group workers", func(t *testing.T) {
+		c := NewSyncCounter[string]()
+		group, _ := result.WithErrorsThreshold[int](context.Background(), 1)
+
+		for range 10 {
+			group.Go(func() ([]int, error) {
+				for _, word := range strings.Fields("a b a") {
+					c.Add(word)
+				}
+
+				return nil, nil
+			})
+		}
+
+		_, err := group.Wait()
+		assert.NoError(t, err)
+
+		assert.Equal(t, 20, c.Get("a"))
+		assert.Equal(t, 30, c.Total())
+		assert.Equal(t, 2, c.Len())
+		assert.Equal(t, []Entry[string, int]{{"a", 20}}, c.MostCommon(1))
+	})
+
+	t.Run("methods", func(t *testing.T) {
+		c := NewSyncCounter[string]()
+		c.AddN("a", 2)
+		c.Add("b")
+
+		assert.Equal(t, []Entry[string, int]{{"b", 1}}, c.LeastCommon(1))
+
+		snapshot := c.Snapshot()
+		c.Add("b")
+
+		assert.Equal(t, 1, snapshot.Get("b"))
+		assert.Equal(t, 2, c.Get("b"))
+	})
+}
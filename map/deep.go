@@ -0,0 +1,280 @@
+package m
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// SliceStrategy decides how DeepMerge combines two slices found at the same path.
+type SliceStrategy int
+
+const (
+	// SliceReplace keeps the slice of the later map.
+	SliceReplace SliceStrategy = iota
+	// SliceAppend appends the elements of the later slice to the earlier one.
+	SliceAppend
+	// SliceUnion appends the elements of the later slice that are not already in the earlier one.
+	SliceUnion
+)
+
+/*
+MergeOptions configures DeepMerge.
+
+Example
+
+	opts := m.MergeOptions{
+		Slices: m.SliceUnion,
+		OnConflict: func(path string, existing, incoming any) any {
+			log.Printf("%s: %v overridden by %v", path, existing, incoming)
+			return incoming
+		},
+	}
+*/
+type MergeOptions struct {
+	// OnConflict resolves a path set in both maps that cannot be merged further, because at least one side is neither
+	// a map nor, for both sides, a slice. Defaults to keeping the later value.
+	OnConflict func(path string, existing, incoming any) any
+	// Slices decides how two slices at the same path are combined. Defaults to SliceReplace.
+	Slices SliceStrategy
+}
+
+/*
+DeepMerge merges nested map[string]any trees, such as the ones produced by structs.ToMap, into a new tree.
+Later maps take precedence over earlier ones. Nested maps are merged key by key, slices according to opts.Slices
+and everything else according to opts.OnConflict. The inputs are not modified.
+
+Example
+
+	defaults := map[string]any{"db": map[string]any{"host": "localhost", "port": 5432}}
+	env := map[string]any{"db": map[string]any{"host": "db.internal"}}
+	config := m.DeepMerge(m.MergeOptions{}, defaults, env)
+	// config == map[string]any{"db": map[string]any{"host": "db.internal", "port": 5432}}
+*/
+func DeepMerge(opts MergeOptions, maps ...map[string]any) map[string]any {
+	result := make(map[string]any)
+	for _, input := range maps {
+		mergeMap(opts, "", result, input)
+	}
+
+	return result
+}
+
+func mergeMap(opts MergeOptions, path string, dst, src map[string]any) {
+	for k, incoming := range src {
+		keyPath := joinPath(path, k)
+
+		existing, ok := dst[k]
+		if !ok {
+			dst[k] = deepCopy(incoming)
+
+			continue
+		}
+
+		dst[k] = mergeValue(opts, keyPath, existing, incoming)
+	}
+}
+
+func mergeValue(opts MergeOptions, path string, existing, incoming any) any {
+	existingMap, existingIsMap := existing.(map[string]any)
+	incomingMap, incomingIsMap := incoming.(map[string]any)
+
+	if existingIsMap && incomingIsMap {
+		mergeMap(opts, path, existingMap, incomingMap)
+
+		return existingMap
+	}
+
+	existingSlice, existingIsSlice := existing.([]any)
+	incomingSlice, incomingIsSlice := incoming.([]any)
+
+	if existingIsSlice && incomingIsSlice {
+		return mergeSlice(opts.Slices, existingSlice, incomingSlice)
+	}
+
+	if opts.OnConflict != nil {
+		return deepCopy(opts.OnConflict(path, existing, incoming))
+	}
+
+	return deepCopy(incoming)
+}
+
+func mergeSlice(strategy SliceStrategy, existing, incoming []any) []any {
+	switch strategy {
+	case SliceAppend:
+		return append(existing, deepCopy(incoming).([]any)...) //nolint:errcheck
+	case SliceUnion:
+		for _, v := range incoming {
+			if !slices.ContainsFunc(existing, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+				existing = append(existing, deepCopy(v))
+			}
+		}
+
+		return existing
+	case SliceReplace:
+		return deepCopy(incoming).([]any) //nolint:errcheck
+	default:
+		panic("unknown slice strategy")
+	}
+}
+
+// deepCopy copies the maps and slices of a map[string]any tree so that merging never aliases the inputs.
+func deepCopy(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, e := range v {
+			result[k] = deepCopy(e)
+		}
+
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, e := range v {
+			result[i] = deepCopy(e)
+		}
+
+		return result
+	default:
+		return v
+	}
+}
+
+// ChangeKind is the kind of a Change.
+type ChangeKind int
+
+const (
+	// ChangeAdded means the path only exists in the new map.
+	ChangeAdded ChangeKind = iota
+	// ChangeRemoved means the path only exists in the old map.
+	ChangeRemoved
+	// ChangeModified means the path exists in both maps with different values.
+	ChangeModified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is a difference found by DeepDiff. Old is nil for added paths and New is nil for removed paths.
+type Change struct {
+	Old  any
+	New  any
+	Path string
+	Kind ChangeKind
+}
+
+/*
+DeepDiff compares two nested map[string]any trees and returns the paths that were added, removed or modified,
+sorted by path. Paths are dotted, with slice indexes as segments, e.g. "db.hosts.0". Nested maps and slices
+are compared element by element; other values are compared with reflect.DeepEqual.
+
+Example
+
+	before := map[string]any{"db": map[string]any{"host": "localhost", "port": 5432}}
+	after := map[string]any{"db": map[string]any{"host": "db.internal", "user": "app"}}
+	changes := m.DeepDiff(before, after)
+	// changes == []m.Change{
+	//	{Path: "db.host", Kind: m.ChangeModified, Old: "localhost", New: "db.internal"},
+	//	{Path: "db.port", Kind: m.ChangeRemoved, Old: 5432},
+	//	{Path: "db.user", Kind: m.ChangeAdded, New: "app"},
+	// }
+*/
+func DeepDiff(before, after map[string]any) []Change {
+	var changes []Change
+
+	diffMap("", before, after, &changes)
+
+	return changes
+}
+
+func diffMap(path string, before, after map[string]any, changes *[]Change) {
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		old, inBefore := before[k]
+		updated, inAfter := after[k]
+		diffValue(joinPath(path, k), old, inBefore, updated, inAfter, changes)
+	}
+}
+
+func diffSlice(path string, before, after []any, changes *[]Change) {
+	for i := range max(len(before), len(after)) {
+		var old, updated any
+
+		inBefore := i < len(before)
+		if inBefore {
+			old = before[i]
+		}
+
+		inAfter := i < len(after)
+		if inAfter {
+			updated = after[i]
+		}
+
+		diffValue(joinPath(path, strconv.Itoa(i)), old, inBefore, updated, inAfter, changes)
+	}
+}
+
+func diffValue(path string, old any, inBefore bool, updated any, inAfter bool, changes *[]Change) {
+	switch {
+	case !inBefore:
+		*changes = append(*changes, Change{Path: path, Kind: ChangeAdded, New: updated})
+
+		return
+	case !inAfter:
+		*changes = append(*changes, Change{Path: path, Kind: ChangeRemoved, Old: old})
+
+		return
+	}
+
+	oldMap, oldIsMap := old.(map[string]any)
+	updatedMap, updatedIsMap := updated.(map[string]any)
+
+	if oldIsMap && updatedIsMap {
+		diffMap(path, oldMap, updatedMap, changes)
+
+		return
+	}
+
+	oldSlice, oldIsSlice := old.([]any)
+	updatedSlice, updatedIsSlice := updated.([]any)
+
+	if oldIsSlice && updatedIsSlice {
+		diffSlice(path, oldSlice, updatedSlice, changes)
+
+		return
+	}
+
+	if !reflect.DeepEqual(old, updated) {
+		*changes = append(*changes, Change{Path: path, Kind: ChangeModified, Old: old, New: updated})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,119 @@
+package m
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepMerge(t *testing.T) {
+	t.Run("merges nested maps", func(t *testing.T) {
+		defaults := map[string]any{"db": map[string]any{"host": "localhost", "port": 5432}, "debug": false}
+		env := map[string]any{"db": map[string]any{"host": "db.internal"}, "debug": true}
+
+		result := DeepMerge(MergeOptions{}, defaults, env)
+
+		assert.Equal(t, map[string]any{"db": map[string]any{"host": "db.internal", "port": 5432}, "debug": true}, result)
+	})
+
+	t.Run("does not modify the inputs", func(t *testing.T) {
+		defaults := map[string]any{"db": map[string]any{"host": "localhost"}, "tags": []any{"a"}}
+		env := map[string]any{"db": map[string]any{"port": 5432}, "tags": []any{"b"}}
+
+		result := DeepMerge(MergeOptions{Slices: SliceAppend}, defaults, env)
+		result["db"].(map[string]any)["user"] = "app" //nolint:errcheck
+		result["tags"].([]any)[0] = "z"               //nolint:errcheck
+
+		assert.Equal(t, map[string]any{"db": map[string]any{"host": "localhost"}, "tags": []any{"a"}}, defaults)
+		assert.Equal(t, map[string]any{"db": map[string]any{"port": 5432}, "tags": []any{"b"}}, env)
+	})
+
+	t.Run("slice strategies", func(t *testing.T) {
+		a := map[string]any{"tags": []any{"a", "b"}}
+		b := map[string]any{"tags": []any{"b", "c"}}
+
+		assert.Equal(t, []any{"b", "c"}, DeepMerge(MergeOptions{Slices: SliceReplace}, a, b)["tags"])
+		assert.Equal(t, []any{"a", "b", "b", "c"}, DeepMerge(MergeOptions{Slices: SliceAppend}, a, b)["tags"])
+		assert.Equal(t, []any{"a", "b", "c"}, DeepMerge(MergeOptions{Slices: SliceUnion}, a, b)["tags"])
+	})
+
+	t.Run("union compares nested values", func(t *testing.T) {
+		a := map[string]any{"users": []any{map[string]any{"name": "ann"}}}
+		b := map[string]any{"users": []any{map[string]any{"name": "ann"}, map[string]any{"name": "bob"}}}
+
+		result := DeepMerge(MergeOptions{Slices: SliceUnion}, a, b)
+
+		assert.Equal(t, []any{map[string]any{"name": "ann"}, map[string]any{"name": "bob"}}, result["users"])
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		var paths []string
+		opts := MergeOptions{
+			OnConflict: func(path string, existing, _ any) any {
+				paths = append(paths, path)
+
+				return existing
+			},
+		}
+		a := map[string]any{"db": map[string]any{"port": 5432, "hosts": []any{"a"}}}
+		b := map[string]any{"db": map[string]any{"port": "5433", "hosts": "b", "user": "app"}}
+
+		result := DeepMerge(opts, a, b)
+
+		assert.Equal(t, map[string]any{"db": map[string]any{"port": 5432, "hosts": []any{"a"}, "user": "app"}}, result)
+		assert.ElementsMatch(t, []string{"db.port", "db.hosts"}, paths)
+	})
+
+	t.Run("no maps", func(t *testing.T) {
+		assert.Equal(t, map[string]any{}, DeepMerge(MergeOptions{}))
+	})
+}
+
+func TestDeepDiff(t *testing.T) {
+	t.Run("reports added, removed and modified paths", func(t *testing.T) {
+		before := map[string]any{"db": map[string]any{"host": "localhost", "port": 5432}, "debug": false}
+		after := map[string]any{"db": map[string]any{"host": "db.internal", "user": "app"}, "debug": false}
+
+		assert.Equal(t, []Change{
+			{Path: "db.host", Kind: ChangeModified, Old: "localhost", New: "db.internal"},
+			{Path: "db.port", Kind: ChangeRemoved, Old: 5432},
+			{Path: "db.user", Kind: ChangeAdded, New: "app"},
+		}, DeepDiff(before, after))
+	})
+
+	t.Run("slices", func(t *testing.T) {
+		before := map[string]any{"hosts": []any{"a", "b", "c"}}
+		after := map[string]any{"hosts": []any{"a", "x"}}
+
+		assert.Equal(t, []Change{
+			{Path: "hosts.1", Kind: ChangeModified, Old: "b", New: "x"},
+			{Path: "hosts.2", Kind: ChangeRemoved, Old: "c"},
+		}, DeepDiff(before, after))
+
+		assert.Equal(t, []Change{
+			{Path: "hosts.2", Kind: ChangeAdded, New: "c"},
+		}, DeepDiff(map[string]any{"hosts": []any{"a", "b"}}, before))
+	})
+
+	t.Run("type changes", func(t *testing.T) {
+		before := map[string]any{"db": map[string]any{"host": "localhost"}}
+		after := map[string]any{"db": "postgres://localhost"}
+
+		assert.Equal(t, []Change{
+			{Path: "db", Kind: ChangeModified, Old: map[string]any{"host": "localhost"}, New: "postgres://localhost"},
+		}, DeepDiff(before, after))
+	})
+
+	t.Run("equal maps", func(t *testing.T) {
+		tree := map[string]any{"db": map[string]any{"hosts": []any{"a"}}}
+
+		assert.Empty(t, DeepDiff(tree, DeepMerge(MergeOptions{}, tree)))
+	})
+
+	t.Run("ChangeKind String", func(t *testing.T) {
+		assert.Equal(t, "added", ChangeAdded.String())
+		assert.Equal(t, "removed", ChangeRemoved.String())
+		assert.Equal(t, "modified", ChangeModified.String())
+		assert.Equal(t, "unknown", ChangeKind(42).String())
+	})
+}
@@ -0,0 +1,320 @@
+package m
+
+import (
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/neurocode-io/go-pkgs/result"
+)
+
+/*
+ExpiringOptions configures an ExpiringMap.
+
+Example
+
+	opts := m.ExpiringOptions[string, *Session]{
+		DefaultTTL:      time.Hour,
+		CleanupInterval: time.Minute,
+		OnEvict: func(key string, session *Session) {
+			session.Close()
+		},
+	}
+*/
+type ExpiringOptions[T comparable, V any] struct {
+	// Clock is used to decide whether entries have expired and to schedule the janitor. Defaults to the system clock.
+	Clock result.Clock
+	// OnEvict is called for every entry removed because it expired.
+	OnEvict func(key T, value V)
+	// DefaultTTL is the time to live of entries stored with Store. Zero means entries never expire; it must not be
+	// negative.
+	DefaultTTL time.Duration
+	// CleanupInterval is how often a background janitor removes expired entries. Zero disables the janitor;
+	// expired entries are then only removed lazily when they are accessed or by DeleteExpired.
+	CleanupInterval time.Duration
+}
+
+type expiringEntry[V any] struct {
+	expiresAt time.Time
+	value     V
+}
+
+func (e expiringEntry[V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+/*
+ExpiringMap is a generic threadsafe map whose entries expire after a time to live.
+
+Example
+
+	cache := NewExpiringMap(m.ExpiringOptions[string, int]{DefaultTTL: time.Minute, CleanupInterval: time.Second})
+	defer cache.Stop()
+	cache.Store("a", 1)
+	cache.Load("a") // 1, true
+*/
+type ExpiringMap[T comparable, V any] struct {
+	opts  ExpiringOptions[T, V]
+	items map[T]expiringEntry[V]
+	stop  chan struct{}
+	once  sync.Once
+	mutex sync.Mutex
+}
+
+// NewExpiringMap returns a new pointer to an ExpiringMap and starts its janitor if a cleanup interval is set.
+// It panics if the default TTL is negative.
+func NewExpiringMap[T comparable, V any](opts ExpiringOptions[T, V]) *ExpiringMap[T, V] {
+	if opts.DefaultTTL < 0 {
+		panic("default TTL must be greater than or equal to 0")
+	}
+
+	if opts.Clock == nil {
+		opts.Clock = result.SystemClock{}
+	}
+
+	m := &ExpiringMap[T, V]{opts: opts, items: make(map[T]expiringEntry[V]), stop: make(chan struct{})}
+
+	if opts.CleanupInterval > 0 {
+		go m.janitor(opts.CleanupInterval)
+	}
+
+	return m
+}
+
+/*
+Store sets the value for a key with the default time to live.
+
+Example
+
+	cache.Store("a", 1)
+*/
+func (m *ExpiringMap[T, V]) Store(key T, value V) {
+	m.StoreWithTTL(key, value, m.opts.DefaultTTL)
+}
+
+/*
+StoreWithTTL sets the value for a key that expires after ttl. A ttl of zero means the entry never expires.
+It panics if ttl is negative.
+
+Example
+
+	cache.StoreWithTTL("a", 1, time.Second)
+*/
+func (m *ExpiringMap[T, V]) StoreWithTTL(key T, value V, ttl time.Duration) {
+	if ttl < 0 {
+		panic("ttl must be greater than or equal to 0")
+	}
+
+	entry := expiringEntry[V]{value: value}
+	if ttl > 0 {
+		entry.expiresAt = m.opts.Clock.Now().Add(ttl)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.items[key] = entry
+}
+
+/*
+Load returns the value and true if the key exists in the map and has not expired, otherwise it returns the zero value and false.
+
+Example
+
+	cache.StoreWithTTL("a", 1, time.Second)
+	cache.Load("a") // 1, true
+	// one second later
+	cache.Load("a") // 0, false
+*/
+func (m *ExpiringMap[T, V]) Load(key T) (value V, ok bool) {
+	entry, ok, expired := m.load(key)
+	if expired {
+		m.evict([]Entry[T, V]{{Key: key, Value: entry.value}})
+	}
+
+	if !ok {
+		return value, false
+	}
+
+	return entry.value, true
+}
+
+/*
+LoadWithExpiration returns the value and the expiration time of a key. The expiration time is zero if the entry never expires.
+
+Example
+
+	value, expiresAt, ok := cache.LoadWithExpiration("a")
+*/
+func (m *ExpiringMap[T, V]) LoadWithExpiration(key T) (value V, expiresAt time.Time, ok bool) {
+	entry, ok, expired := m.load(key)
+	if expired {
+		m.evict([]Entry[T, V]{{Key: key, Value: entry.value}})
+	}
+
+	if !ok {
+		return value, expiresAt, false
+	}
+
+	return entry.value, entry.expiresAt, true
+}
+
+/*
+Delete deletes the value for a key. If the key does not exist, it does nothing. OnEvict is not called.
+
+Example
+
+	cache.Delete("a")
+*/
+func (m *ExpiringMap[T, V]) Delete(key T) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.items, key)
+}
+
+/*
+Len returns the number of entries that have not expired.
+
+Example
+
+	cache.Store("a", 1)
+	cache.Len() // 1
+*/
+func (m *ExpiringMap[T, V]) Len() int {
+	now := m.opts.Clock.Now()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	n := 0
+	for _, entry := range m.items {
+		if !entry.expired(now) {
+			n++
+		}
+	}
+
+	return n
+}
+
+/*
+Range calls fn sequentially for each key and value that has not expired. If fn returns false, range stops the iteration.
+The entries are copied before fn is called, so fn may modify the map.
+
+Example
+
+	cache.Range(func(key string, value int) bool {
+		fmt.Printf("%s: %d", key, value)
+		return true
+	})
+*/
+func (m *ExpiringMap[T, V]) Range(fn func(key T, value V) bool) {
+	now := m.opts.Clock.Now()
+
+	m.mutex.Lock()
+	entries := make([]Entry[T, V], 0, len(m.items))
+	for k, entry := range m.items {
+		if !entry.expired(now) {
+			entries = append(entries, Entry[T, V]{Key: k, Value: entry.value})
+		}
+	}
+	m.mutex.Unlock()
+
+	for _, entry := range entries {
+		if !fn(entry.Key, entry.Value) {
+			return
+		}
+	}
+}
+
+/*
+All returns an iterator over the keys and values that have not expired.
+
+Example
+
+	for key, value := range cache.All() {
+		fmt.Printf("%s: %d", key, value)
+	}
+*/
+func (m *ExpiringMap[T, V]) All() iter.Seq2[T, V] {
+	return m.Range
+}
+
+/*
+DeleteExpired removes all expired entries and calls OnEvict for each of them. The janitor calls it periodically.
+
+Example
+
+	cache.DeleteExpired()
+*/
+func (m *ExpiringMap[T, V]) DeleteExpired() {
+	now := m.opts.Clock.Now()
+
+	m.mutex.Lock()
+	var evicted []Entry[T, V]
+	for k, entry := range m.items {
+		if entry.expired(now) {
+			delete(m.items, k)
+			evicted = append(evicted, Entry[T, V]{Key: k, Value: entry.value})
+		}
+	}
+	m.mutex.Unlock()
+
+	m.evict(evicted)
+}
+
+/*
+Stop stops the janitor. It is safe to call Stop more than once. The map remains usable with lazy eviction.
+
+Example
+
+	cache := NewExpiringMap(m.ExpiringOptions[string, int]{CleanupInterval: time.Second})
+	defer cache.Stop()
+*/
+func (m *ExpiringMap[T, V]) Stop() {
+	m.once.Do(func() {
+		close(m.stop)
+	})
+}
+
+// load returns the entry for key and whether it is live. An expired entry is removed and reported.
+func (m *ExpiringMap[T, V]) load(key T) (entry expiringEntry[V], ok, expired bool) {
+	now := m.opts.Clock.Now()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok = m.items[key]
+	if !ok {
+		return entry, false, false
+	}
+
+	if entry.expired(now) {
+		delete(m.items, key)
+
+		return entry, false, true
+	}
+
+	return entry, true, false
+}
+
+func (m *ExpiringMap[T, V]) evict(entries []Entry[T, V]) {
+	if m.opts.OnEvict == nil {
+		return
+	}
+
+	for _, entry := range entries {
+		m.opts.OnEvict(entry.Key, entry.Value)
+	}
+}
+
+func (m *ExpiringMap[T, V]) janitor(interval time.Duration) {
+	for {
+		select {
+		case <-m.opts.Clock.After(interval):
+			m.DeleteExpired()
+		case <-m.stop:
+			return
+		}
+	}
+}
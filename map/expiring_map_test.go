@@ -0,0 +1,155 @@
+package m
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neurocode-io/go-pkgs/result/resulttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiringMap(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("default TTL", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(start)
+		m := NewExpiringMap(ExpiringOptions[string, int]{DefaultTTL: time.Minute, Clock: clock})
+		m.Store("a", 1)
+
+		v, ok := m.Load("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+
+		clock.Advance(59 * time.Second)
+		_, ok = m.Load("a")
+		assert.True(t, ok)
+
+		clock.Advance(time.Second)
+		v, ok = m.Load("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, v)
+	})
+
+	t.Run("per-entry TTL", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(start)
+		m := NewExpiringMap(ExpiringOptions[string, int]{DefaultTTL: time.Minute, Clock: clock})
+		m.StoreWithTTL("a", 1, time.Second)
+		m.StoreWithTTL("b", 2, 0)
+
+		_, expiresAt, ok := m.LoadWithExpiration("a")
+		assert.True(t, ok)
+		assert.Equal(t, start.Add(time.Second), expiresAt)
+
+		clock.Advance(time.Hour)
+		_, ok = m.Load("a")
+		assert.False(t, ok)
+
+		v, expiresAt, ok := m.LoadWithExpiration("b")
+		assert.True(t, ok)
+		assert.Equal(t, 2, v)
+		assert.True(t, expiresAt.IsZero())
+	})
+
+	t.Run("no default TTL", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(start)
+		m := NewExpiringMap(ExpiringOptions[string, int]{Clock: clock})
+		m.Store("a", 1)
+
+		clock.Advance(24 * time.Hour)
+		_, ok := m.Load("a")
+		assert.True(t, ok)
+	})
+
+	t.Run("negative TTL panics", func(t *testing.T) {
+		m := NewExpiringMap(ExpiringOptions[string, int]{})
+
+		assert.Panics(t, func() { m.StoreWithTTL("a", 1, -time.Second) })
+		assert.Panics(t, func() { NewExpiringMap(ExpiringOptions[string, int]{DefaultTTL: -time.Second}) })
+
+		_, ok := m.Load("a")
+		assert.False(t, ok)
+	})
+
+	t.Run("lazy eviction calls OnEvict", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(start)
+		var evicted []Entry[string, int]
+		m := NewExpiringMap(ExpiringOptions[string, int]{
+			DefaultTTL: time.Minute,
+			Clock:      clock,
+			OnEvict: func(key string, value int) {
+				evicted = append(evicted, Entry[string, int]{key, value})
+			},
+		})
+		m.Store("a", 1)
+		m.Store("b", 2)
+		m.Delete("b")
+
+		clock.Advance(time.Minute)
+		_, ok := m.Load("a")
+		assert.False(t, ok)
+		_, _, ok = m.LoadWithExpiration("a")
+		assert.False(t, ok)
+
+		assert.Equal(t, []Entry[string, int]{{"a", 1}}, evicted)
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(start)
+		var evicted []string
+		m := NewExpiringMap(ExpiringOptions[string, int]{
+			Clock: clock,
+			OnEvict: func(key string, _ int) {
+				evicted = append(evicted, key)
+			},
+		})
+		m.StoreWithTTL("a", 1, time.Second)
+		m.StoreWithTTL("b", 2, time.Minute)
+		m.Store("c", 3)
+
+		clock.Advance(time.Second)
+		assert.Equal(t, 2, m.Len())
+
+		m.DeleteExpired()
+		assert.Equal(t, []string{"a"}, evicted)
+		assert.Len(t, m.items, 2)
+
+		var keys []string
+		m.Range(func(key string, _ int) bool {
+			keys = append(keys, key)
+
+			return true
+		})
+		assert.ElementsMatch(t, []string{"b", "c"}, keys)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(start)
+		evicted := make(chan string, 1)
+		m := NewExpiringMap(ExpiringOptions[string, int]{
+			Clock:           clock,
+			DefaultTTL:      time.Minute,
+			CleanupInterval: time.Second,
+			OnEvict: func(key string, _ int) {
+				evicted <- key
+			},
+		})
+		defer m.Stop()
+
+		m.Store("a", 1)
+		waitForJanitor(t, clock)
+
+		clock.Advance(30 * time.Second)
+		waitForJanitor(t, clock)
+		assert.Empty(t, evicted)
+
+		clock.Advance(30 * time.Second)
+		assert.Equal(t, "a", <-evicted)
+	})
+}
+
+// waitForJanitor waits until the janitor is blocked on the clock.
+func waitForJanitor(t *testing.T, clock *resulttest.FakeClock) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool { return clock.Waiters() == 1 }, time.Second, time.Millisecond)
+}
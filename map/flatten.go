@@ -0,0 +1,170 @@
+package m
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+FlattenOptions configures Flatten and Unflatten.
+
+Example
+
+	opts := m.FlattenOptions{Separator: "_", MaxDepth: 2}
+*/
+type FlattenOptions struct {
+	// Separator joins the keys of nested maps. Defaults to ".".
+	Separator string
+	// MaxDepth is the maximum number of keys joined in a flat key; deeper maps and slices are kept as values.
+	// Zero means no limit. Unflatten ignores it.
+	MaxDepth int
+	// KeepSlices keeps slices as values instead of flattening them with their indexes as keys.
+	KeepSlices bool
+}
+
+func (o FlattenOptions) separator() string {
+	if o.Separator == "" {
+		return "."
+	}
+
+	return o.Separator
+}
+
+/*
+Flatten turns a nested map[string]any tree, such as the output of structs.ToMap, into a flat map whose keys are the
+paths of the leaves. Slice elements are keyed by their index unless opts.KeepSlices is set. Empty maps and slices are
+kept as values, so that Unflatten can restore them.
+
+Example
+
+	input := map[string]any{"db": map[string]any{"hosts": []any{"x", "y"}, "port": 5432}}
+	result := m.Flatten(input, m.FlattenOptions{})
+	// result == map[string]any{"db.hosts.0": "x", "db.hosts.1": "y", "db.port": 5432}
+*/
+func Flatten(input map[string]any, opts FlattenOptions) map[string]any {
+	result := make(map[string]any)
+	for k, v := range input {
+		flatten(result, opts, k, 1, v)
+	}
+
+	return result
+}
+
+func flatten(result map[string]any, opts FlattenOptions, key string, depth int, value any) {
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		result[key] = value
+
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		flattenMap(result, opts, key, depth, v)
+	case Tree:
+		flattenMap(result, opts, key, depth, v)
+	case []any:
+		if opts.KeepSlices || len(v) == 0 {
+			result[key] = value
+
+			return
+		}
+
+		for i, e := range v {
+			flatten(result, opts, key+opts.separator()+strconv.Itoa(i), depth+1, e)
+		}
+	default:
+		result[key] = value
+	}
+}
+
+func flattenMap(result map[string]any, opts FlattenOptions, key string, depth int, value map[string]any) {
+	if len(value) == 0 {
+		result[key] = value
+
+		return
+	}
+
+	for k, e := range value {
+		flatten(result, opts, key+opts.separator()+k, depth+1, e)
+	}
+}
+
+/*
+Unflatten rebuilds a nested map[string]any tree from a flat map produced by Flatten. Unless opts.KeepSlices is set,
+nested maps whose keys are exactly the indexes 0 to n-1 become slices. It returns ErrInvalidPath if a key is both a
+leaf and the parent of another key, e.g. "db" and "db.port". The values are copied, so the input is not modified.
+
+Example
+
+	input := map[string]any{"db.hosts.0": "x", "db.hosts.1": "y", "db.port": 5432}
+	result, err := m.Unflatten(input, m.FlattenOptions{})
+	// result == map[string]any{"db": map[string]any{"hosts": []any{"x", "y"}, "port": 5432}}
+*/
+func Unflatten(input map[string]any, opts FlattenOptions) (map[string]any, error) {
+	type flatKey struct {
+		key      string
+		segments []string
+	}
+
+	keys := make([]flatKey, 0, len(input))
+	for k := range input {
+		keys = append(keys, flatKey{key: k, segments: strings.Split(k, opts.separator())})
+	}
+
+	// Setting parents before their children turns every leaf and parent clash into an error, whatever the map order.
+	slices.SortFunc(keys, func(a, b flatKey) int {
+		return cmp.Or(cmp.Compare(len(a.segments), len(b.segments)), strings.Compare(a.key, b.key))
+	})
+
+	result := make(map[string]any, len(input))
+	for _, k := range keys {
+		if _, err := setPath(result, k.segments, deepCopy(input[k.key])); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, k.key)
+		}
+	}
+
+	if !opts.KeepSlices {
+		for k, v := range result {
+			result[k] = indexMapsToSlices(v)
+		}
+	}
+
+	return result, nil
+}
+
+// indexMapsToSlices replaces the maps below value whose keys are exactly 0 to n-1 with slices.
+func indexMapsToSlices(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = indexMapsToSlices(e)
+		}
+
+		if len(v) == 0 {
+			return v
+		}
+
+		result := make([]any, len(v))
+		for k, e := range v {
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(v) || strconv.Itoa(i) != k {
+				return v
+			}
+
+			result[i] = e
+		}
+
+		return result
+	case []any:
+		for i, e := range v {
+			v[i] = indexMapsToSlices(e)
+		}
+
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,118 @@
+package m
+
+import (
+	"testing"
+
+	"github.com/neurocode-io/go-pkgs/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	nested := map[string]any{
+		"db": map[string]any{
+			"hosts": []any{"x", map[string]any{"name": "y"}},
+			"port":  5432,
+			"tags":  []any{},
+			"opts":  map[string]any{},
+		},
+		"debug": true,
+	}
+
+	t.Run("default options", func(t *testing.T) {
+		assert.Equal(t, map[string]any{
+			"db.hosts.0":      "x",
+			"db.hosts.1.name": "y",
+			"db.port":         5432,
+			"db.tags":         []any{},
+			"db.opts":         map[string]any{},
+			"debug":           true,
+		}, Flatten(nested, FlattenOptions{}))
+	})
+
+	t.Run("separator and kept slices", func(t *testing.T) {
+		assert.Equal(t, map[string]any{
+			"db_hosts": []any{"x", map[string]any{"name": "y"}},
+			"db_port":  5432,
+			"db_tags":  []any{},
+			"db_opts":  map[string]any{},
+			"debug":    true,
+		}, Flatten(nested, FlattenOptions{Separator: "_", KeepSlices: true}))
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		assert.Equal(t, nested, Flatten(nested, FlattenOptions{MaxDepth: 1}))
+		assert.Equal(t, map[string]any{
+			"db.hosts.0": "x",
+			"db.hosts.1": map[string]any{"name": "y"},
+			"db.port":    5432,
+			"db.tags":    []any{},
+			"db.opts":    map[string]any{},
+			"debug":      true,
+		}, Flatten(nested, FlattenOptions{MaxDepth: 3}))
+	})
+
+	t.Run("structs.ToMap output", func(t *testing.T) {
+		type DB struct {
+			Hosts []string
+		}
+
+		type Config struct {
+			DB DB
+		}
+
+		input := structs.ToMap(Config{DB: DB{Hosts: []string{"a", "b"}}}).(map[string]any) //nolint:errcheck
+
+		assert.Equal(t, map[string]any{"DB.Hosts.0": "a", "DB.Hosts.1": "b"}, Flatten(input, FlattenOptions{}))
+	})
+}
+
+func TestUnflatten(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		nested := map[string]any{
+			"db": map[string]any{
+				"hosts": []any{"x", map[string]any{"name": "y"}},
+				"port":  5432,
+				"tags":  []any{},
+				"opts":  map[string]any{},
+			},
+			"debug": true,
+		}
+
+		for _, opts := range []FlattenOptions{{}, {Separator: "/"}, {KeepSlices: true}, {MaxDepth: 2}} {
+			result, err := Unflatten(Flatten(nested, opts), opts)
+			assert.NoError(t, err)
+			assert.Equal(t, nested, result, "%+v", opts)
+		}
+	})
+
+	t.Run("index maps become slices", func(t *testing.T) {
+		result, err := Unflatten(map[string]any{"a.1": "y", "a.0": "x", "b.0": 1, "b.2": 2, "c.01": 1}, FlattenOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"a": []any{"x", "y"},
+			"b": map[string]any{"0": 1, "2": 2},
+			"c": map[string]any{"01": 1},
+		}, result)
+
+		result, err = Unflatten(map[string]any{"a.0": "x"}, FlattenOptions{KeepSlices: true})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": map[string]any{"0": "x"}}, result)
+	})
+
+	t.Run("does not modify the input", func(t *testing.T) {
+		input := map[string]any{"db": map[string]any{"host": "x"}, "db.port": 1}
+
+		result, err := Unflatten(input, FlattenOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"db": map[string]any{"host": "x", "port": 1}}, result)
+		assert.Equal(t, map[string]any{"db": map[string]any{"host": "x"}, "db.port": 1}, input)
+	})
+
+	t.Run("leaf and parent clash", func(t *testing.T) {
+		for range 10 {
+			_, err := Unflatten(map[string]any{"db": 1, "db.port": 2}, FlattenOptions{})
+			assert.ErrorIs(t, err, ErrInvalidPath)
+			assert.ErrorContains(t, err, "db.port")
+		}
+	})
+}
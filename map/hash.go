@@ -0,0 +1,131 @@
+package m
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+/*
+NewHasher returns a seeded hash function for keys of type K, as used by ShardedMap when no hasher is given.
+Strings and integers are hashed directly. Other key types, such as structs and arrays, are hashed field by field
+through reflection, which is slower; supply a custom hasher for them on hot paths. Keys that are == hash the same,
+including floats such as -0.0 and +0.0.
+
+Example
+
+	hasher := m.NewHasher[string]()
+	hasher("a") == hasher("a") // true
+*/
+func NewHasher[K comparable]() func(key K) uint64 {
+	seed := maphash.MakeSeed()
+
+	return func(key K) uint64 {
+		return hashKey(seed, key)
+	}
+}
+
+func hashKey[K comparable](seed maphash.Seed, key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		return hashUint64(seed, uint64(k)) //nolint:gosec
+	case int8:
+		return hashUint64(seed, uint64(k)) //nolint:gosec
+	case int16:
+		return hashUint64(seed, uint64(k)) //nolint:gosec
+	case int32:
+		return hashUint64(seed, uint64(k)) //nolint:gosec
+	case int64:
+		return hashUint64(seed, uint64(k)) //nolint:gosec
+	case uint:
+		return hashUint64(seed, uint64(k))
+	case uint8:
+		return hashUint64(seed, uint64(k))
+	case uint16:
+		return hashUint64(seed, uint64(k))
+	case uint32:
+		return hashUint64(seed, uint64(k))
+	case uint64:
+		return hashUint64(seed, k)
+	case uintptr:
+		return hashUint64(seed, uint64(k))
+	}
+
+	var h maphash.Hash
+	h.SetSeed(seed)
+	hashValue(&h, reflect.ValueOf(key))
+
+	return h.Sum64()
+}
+
+// hashValue writes v to h so that values which are == write the same bytes.
+func hashValue(h *maphash.Hash, v reflect.Value) {
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Invalid:
+		writeUint64(h, 0)
+	case reflect.String:
+		// The length keeps adjacent string fields such as {"ab", "c"} and {"a", "bc"} apart.
+		writeUint64(h, uint64(v.Len()))
+		h.WriteString(v.String()) //nolint:errcheck
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(h, uint64(v.Int())) //nolint:gosec
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(h, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat(h, real(c))
+		writeFloat(h, imag(c))
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint64(h, 1)
+		} else {
+			writeUint64(h, 0)
+		}
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Chan:
+		writeUint64(h, uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			writeUint64(h, 0)
+		} else {
+			hashValue(h, v.Elem())
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			hashValue(h, v.Field(i))
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			hashValue(h, v.Index(i))
+		}
+	default:
+		// Maps, slices and funcs are not comparable, so they cannot be keys.
+		panic("unhashable key type " + v.Type().String())
+	}
+}
+
+func writeFloat(h *maphash.Hash, f float64) {
+	if f == 0 {
+		// -0.0 == +0.0, so both must hash the same.
+		f = 0
+	}
+
+	writeUint64(h, math.Float64bits(f))
+}
+
+func writeUint64(h *maphash.Hash, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	h.Write(b[:]) //nolint:errcheck
+}
+
+func hashUint64(seed maphash.Seed, v uint64) uint64 {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+
+	return maphash.Bytes(seed, b[:])
+}
@@ -0,0 +1,91 @@
+package m
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHasher(t *testing.T) {
+	t.Run("equal keys hash the same", func(t *testing.T) {
+		type point struct{ X, Y int }
+		type id string
+
+		s := NewHasher[string]()
+		assert.Equal(t, s("a"), s("a"))
+
+		i := NewHasher[int]()
+		assert.Equal(t, i(1), i(1))
+
+		named := NewHasher[id]()
+		assert.Equal(t, named("a"), named("a"))
+
+		f := NewHasher[float64]()
+		assert.Equal(t, f(0), f(math.Copysign(0, -1)))
+
+		p := NewHasher[point]()
+		assert.Equal(t, p(point{1, 2}), p(point{1, 2}))
+
+		x := 1
+		ptr := NewHasher[*int]()
+		assert.Equal(t, ptr(&x), ptr(&x))
+
+		a := NewHasher[any]()
+		assert.Equal(t, a("a"), a("a"))
+		assert.Equal(t, a(nil), a(nil))
+	})
+
+	t.Run("equal structs and arrays hash the same", func(t *testing.T) {
+		type pt struct {
+			X    float64
+			Name string
+			Tags [2]any
+		}
+
+		negZero := math.Copysign(0, -1)
+
+		p := NewHasher[pt]()
+		assert.Equal(t, p(pt{X: 0}), p(pt{X: negZero}))
+		assert.Equal(t, p(pt{Tags: [2]any{0.0, "a"}}), p(pt{Tags: [2]any{negZero, "a"}}))
+		assert.NotEqual(t, p(pt{Name: "a"}), p(pt{Name: "b"}))
+
+		arr := NewHasher[[2]float32]()
+		assert.Equal(t, arr([2]float32{0, 1}), arr([2]float32{float32(negZero), 1}))
+
+		type pair struct{ A, B string }
+
+		pairs := NewHasher[pair]()
+		assert.NotEqual(t, pairs(pair{"ab", "c"}), pairs(pair{"a", "bc"}))
+	})
+
+	t.Run("equal struct keys share an entry", func(t *testing.T) {
+		type pt struct{ X float64 }
+
+		sm := NewShardedMap[pt, int](64, nil)
+		sm.Store(pt{0}, 1)
+		sm.Store(pt{math.Copysign(0, -1)}, 2)
+		assert.Equal(t, 1, sm.Len())
+
+		pm := NewPersistentMap[pt, int](nil).Set(pt{0}, 1).Set(pt{math.Copysign(0, -1)}, 2)
+		assert.Equal(t, 1, pm.Len())
+	})
+
+	t.Run("string and integer keys do not allocate", func(t *testing.T) {
+		s := NewHasher[string]()
+		i := NewHasher[int64]()
+
+		assert.Zero(t, testing.AllocsPerRun(100, func() {
+			s("key")
+			i(42)
+		}))
+	})
+
+	t.Run("different keys hash differently", func(t *testing.T) {
+		s := NewHasher[string]()
+		assert.NotEqual(t, s("a"), s("b"))
+
+		b := NewHasher[bool]()
+		assert.NotEqual(t, b(true), b(false))
+	})
+}
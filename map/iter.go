@@ -0,0 +1,139 @@
+package m
+
+import (
+	"iter"
+)
+
+/*
+All returns an iterator over the keys and values of the map.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2}
+	for key, value := range m.All(input) {
+		fmt.Printf("%s: %d", key, value)
+	}
+*/
+func All[K comparable, V any](input map[K]V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range input {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+/*
+KeysSeq returns an iterator over the keys of the map. Unlike Keys, it does not build a slice.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2}
+	for key := range m.KeysSeq(input) {
+		fmt.Println(key)
+	}
+*/
+func KeysSeq[K comparable, V any](input map[K]V) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range input {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+/*
+ValuesSeq returns an iterator over the values of the map. Unlike Values, it does not build a slice.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2}
+	for value := range m.ValuesSeq(input) {
+		fmt.Println(value)
+	}
+*/
+func ValuesSeq[K comparable, V any](input map[K]V) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range input {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+/*
+EntriesSeq returns an iterator over the entries of the map. Unlike Entries, it does not build a slice.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2}
+	for entry := range m.EntriesSeq(input) {
+		fmt.Printf("%s: %d", entry.Key, entry.Value)
+	}
+*/
+func EntriesSeq[K comparable, V any](input map[K]V) iter.Seq[Entry[K, V]] {
+	return func(yield func(Entry[K, V]) bool) {
+		for k, v := range input {
+			if !yield(Entry[K, V]{Key: k, Value: v}) {
+				return
+			}
+		}
+	}
+}
+
+/*
+Collect returns a map with the keys and values of the iterator. Later values overwrite earlier ones for the same key.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Store("a", 1)
+	snapshot := m.Collect(asyncMap.All())
+	// snapshot == map[string]int{"a": 1}
+*/
+func Collect[K comparable, V any](seq iter.Seq2[K, V]) map[K]V {
+	result := make(map[K]V)
+	for k, v := range seq {
+		result[k] = v
+	}
+
+	return result
+}
+
+/*
+CollectEntries returns a slice with the keys and values of the iterator in iteration order.
+
+Example
+
+	om := m.NewOrderedMap[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+	m.CollectEntries(om.All()) // []m.Entry[string, int]{{"b", 2}, {"a", 1}}
+*/
+func CollectEntries[K comparable, V any](seq iter.Seq2[K, V]) []Entry[K, V] {
+	var result []Entry[K, V]
+	for k, v := range seq {
+		result = append(result, Entry[K, V]{Key: k, Value: v})
+	}
+
+	return result
+}
+
+/*
+CollectOrdered returns an OrderedMap with the keys and values of the iterator in iteration order.
+
+Example
+
+	om := m.CollectOrdered(m.Sort(input).All())
+*/
+func CollectOrdered[K comparable, V any](seq iter.Seq2[K, V]) *OrderedMap[K, V] {
+	result := NewOrderedMap[K, V]()
+	for k, v := range seq {
+		result.Set(k, v)
+	}
+
+	return result
+}
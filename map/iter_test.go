@@ -0,0 +1,89 @@
+package m
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIter(t *testing.T) {
+	input := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	t.Run("All", func(t *testing.T) {
+		assert.Equal(t, input, Collect(All(input)))
+
+		runs := 0
+		for range All(input) {
+			runs++
+
+			break
+		}
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("KeysSeq/ValuesSeq", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, slices.Collect(KeysSeq(input)))
+		assert.ElementsMatch(t, []int{1, 2, 3}, slices.Collect(ValuesSeq(input)))
+
+		for range KeysSeq(input) {
+			break
+		}
+		for range ValuesSeq(input) {
+			break
+		}
+	})
+
+	t.Run("EntriesSeq", func(t *testing.T) {
+		assert.ElementsMatch(t, Entries(input), slices.Collect(EntriesSeq(input)))
+
+		for range EntriesSeq(input) {
+			break
+		}
+	})
+
+	t.Run("CollectEntries/CollectOrdered", func(t *testing.T) {
+		sorted := Sort(input)
+
+		assert.Equal(t, []Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}, CollectEntries(sorted.All()))
+		assert.Equal(t, []string{"a", "b", "c"}, CollectOrdered(sorted.All()).Keys())
+	})
+
+	t.Run("AsyncMap.All", func(t *testing.T) {
+		m := NewAsyncMap[string, int]()
+		for k, v := range input {
+			m.Store(k, v)
+		}
+
+		assert.Equal(t, input, Collect(m.All()))
+	})
+
+	t.Run("ShardedMap.All", func(t *testing.T) {
+		m := NewShardedMap[string, int](4, nil)
+		for k, v := range input {
+			m.Store(k, v)
+		}
+
+		assert.Equal(t, input, maps.Collect(m.All()))
+	})
+
+	t.Run("OrderedMap.All", func(t *testing.T) {
+		om := NewOrderedMap[string, int]()
+		om.Set("b", 2)
+		om.Set("a", 1)
+
+		var keys []string
+		for k := range om.All() {
+			keys = append(keys, k)
+		}
+		assert.Equal(t, []string{"b", "a"}, keys)
+	})
+
+	t.Run("ExpiringMap.All", func(t *testing.T) {
+		m := NewExpiringMap(ExpiringOptions[string, int]{})
+		m.Store("a", 1)
+
+		assert.Equal(t, map[string]int{"a": 1}, Collect(m.All()))
+	})
+}
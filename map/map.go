@@ -10,11 +10,18 @@
 package m
 
 import (
-	"sort"
+	"cmp"
+	"slices"
 
 	"golang.org/x/exp/constraints"
 )
 
+// Entry is a key/value pair of a map.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
 /*
 Keys returns a slice of the keys in the map.
 
@@ -76,12 +83,12 @@ Example
 
 	input := map[string]int{"a": 1, "b": 2, "c": 3}
 	entries := m.Entries(input)
-	// entries == [][2]string{{"a", 1}, {"b", 2}, {"c", 3}}
+	// entries == []m.Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}
 */
-func Entries[T comparable](input map[T]T) [][2]T {
-	result := make([][2]T, 0, len(input))
-	for i, j := range input {
-		result = append(result, [2]T{i, j})
+func Entries[K comparable, V any](input map[K]V) []Entry[K, V] {
+	result := make([]Entry[K, V], 0, len(input))
+	for k, v := range input {
+		result = append(result, Entry[K, V]{Key: k, Value: v})
 	}
 
 	return result
@@ -92,51 +99,83 @@ FromEntries returns a map from the entries in the slice.
 
 Example
 
-	input := [][2]string{{"a", 1}, {"b", 2}, {"c", 3}}
+	input := []m.Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}
 	m := m.FromEntries(input)
 	// m == map[string]int{"a": 1, "b": 2, "c": 3}
 */
-func FromEntries[T comparable](entries [][2]T) map[T]T {
-	result := make(map[T]T)
+func FromEntries[K comparable, V any](entries []Entry[K, V]) map[K]V {
+	result := make(map[K]V, len(entries))
 	for _, entry := range entries {
-		result[entry[0]] = entry[1]
+		result[entry.Key] = entry.Value
 	}
 
 	return result
 }
 
 /*
-SortByValue returns a map sorted by the values.
+SortByValue returns an OrderedMap with the keys ordered by their values. The order of keys with equal values is
+unspecified; use SortByValueKeyFunc to break ties.
 
 Example
 
 	input := map[string]int{"a": 9, "b": 1, "c": 3}
-	m := m.SortByValue(input)
-	// m == map[string]int{"b": 1, "c": 3, "a": 9}
+	om := m.SortByValue(input)
+	// om.Keys() == []string{"b", "c", "a"}
+*/
+func SortByValue[K comparable, V constraints.Ordered](input map[K]V) *OrderedMap[K, V] {
+	return SortByValueFunc(input, cmp.Compare[V])
+}
+
+/*
+SortByValueFunc returns an OrderedMap with the keys ordered by their values as defined by cmp.
+cmp returns a negative number when a < b, a positive number when a > b and zero when a == b.
+The order of keys with equal values is unspecified; use SortByValueKeyFunc to break ties.
+
+Example
+
+	input := map[string][]int{"a": {1, 2, 3}, "b": {1}}
+	om := m.SortByValueFunc(input, func(a, b []int) int { return len(a) - len(b) })
+	// om.Keys() == []string{"b", "a"}
+*/
+func SortByValueFunc[K comparable, V any](input map[K]V, cmp func(a, b V) int) *OrderedMap[K, V] {
+	return SortByValueKeyFunc(input, cmp, func(K, K) int { return 0 })
+}
+
+/*
+SortByValueKeyFunc returns an OrderedMap with the keys ordered by their values as defined by cmpValue, and keys with
+equal values ordered by cmpKey. The order is deterministic as long as cmpKey only returns zero for equal keys.
+
+Example
+
+	input := map[string]int{"b": 1, "a": 1, "c": 0}
+	om := m.SortByValueKeyFunc(input, cmp.Compare[int], strings.Compare)
+	// om.Keys() == []string{"c", "a", "b"}
 */
-func SortByValue[T constraints.Ordered](input map[T]T) map[T]T {
+func SortByValueKeyFunc[K comparable, V any](
+	input map[K]V, cmpValue func(a, b V) int, cmpKey func(a, b K) int,
+) *OrderedMap[K, V] {
 	entries := Entries(input)
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i][1] < entries[j][1]
+	slices.SortFunc(entries, func(a, b Entry[K, V]) int {
+		return cmp.Or(cmpValue(a.Value, b.Value), cmpKey(a.Key, b.Key))
 	})
 
-	return FromEntries(entries)
+	return orderedMapFromSorted(entries, nil)
 }
 
 /*
-Sort returns a map sorted by the keys.
+Sort returns an OrderedMap sorted by the keys. The map stays sorted when keys are added later.
 
 Example
 
 	input := map[string]string{"b": "world", "a": "hello", "c": "!"}
-	m := m.Sort(input)
-	// m == map[string]string{"a": "hello", "b": "world", "c": "!"}
+	om := m.Sort(input)
+	// om.Keys() == []string{"a", "b", "c"}
 */
-func Sort[T constraints.Ordered](input map[T]T) map[T]T {
+func Sort[K constraints.Ordered, V any](input map[K]V) *OrderedMap[K, V] {
 	entries := Entries(input)
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i][0] < entries[j][0]
+	slices.SortFunc(entries, func(a, b Entry[K, V]) int {
+		return cmp.Compare(a.Key, b.Key)
 	})
 
-	return FromEntries(entries)
+	return orderedMapFromSorted(entries, cmp.Compare[K])
 }
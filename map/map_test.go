@@ -1,6 +1,8 @@
 package m
 
 import (
+	"cmp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,33 +35,73 @@ func TestMap(t *testing.T) {
 
 	t.Run("Entries", func(t *testing.T) {
 		m := map[string]string{"a": "a", "b": "b", "c": "c"}
-		assert.ElementsMatch(t, [][2]string{{"a", "a"}, {"b", "b"}, {"c", "c"}}, Entries(m))
+		assert.ElementsMatch(t, []Entry[string, string]{{"a", "a"}, {"b", "b"}, {"c", "c"}}, Entries(m))
 
 		m2 := map[int]int{1: 1, 2: 2, 3: 3}
-		assert.ElementsMatch(t, [][2]int{{1, 1}, {2, 2}, {3, 3}}, Entries(m2))
+		assert.ElementsMatch(t, []Entry[int, int]{{1, 1}, {2, 2}, {3, 3}}, Entries(m2))
+
+		m3 := map[string]int{"a": 1, "b": 2}
+		assert.ElementsMatch(t, []Entry[string, int]{{"a", 1}, {"b", 2}}, Entries(m3))
 	})
 
 	t.Run("FromEntries", func(t *testing.T) {
-		m := [][2]string{{"a", "a"}, {"b", "b"}, {"c", "c"}}
+		m := []Entry[string, string]{{"a", "a"}, {"b", "b"}, {"c", "c"}}
 		assert.Equal(t, map[string]string{"a": "a", "b": "b", "c": "c"}, FromEntries(m))
 
-		m2 := [][2]int{{1, 1}, {2, 2}, {3, 3}}
+		m2 := []Entry[int, int]{{1, 1}, {2, 2}, {3, 3}}
 		assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 3}, FromEntries(m2))
+
+		m3 := []Entry[string, int]{{"a", 1}, {"b", 2}}
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, FromEntries(m3))
 	})
 
 	t.Run("SortByValue", func(t *testing.T) {
 		m := map[string]string{"a": "c", "b": "a", "c": "b"}
-		assert.Equal(t, map[string]string{"b": "a", "c": "b", "a": "c"}, SortByValue(m))
+		sorted := SortByValue(m)
+		assert.Equal(t, []string{"b", "c", "a"}, sorted.Keys())
+		assert.Equal(t, []string{"a", "b", "c"}, sorted.Values())
 
 		m2 := map[int]int{1: 3, 2: 1, 3: 2}
-		assert.Equal(t, map[int]int{2: 1, 3: 2, 1: 3}, SortByValue(m2))
+		sorted2 := SortByValue(m2)
+		assert.Equal(t, []int{2, 3, 1}, sorted2.Keys())
+		assert.Equal(t, []int{1, 2, 3}, sorted2.Values())
+
+		m3 := map[string]float64{"a": 9.5, "b": 1.5, "c": 3.5}
+		sorted3 := SortByValue(m3)
+		assert.Equal(t, []string{"b", "c", "a"}, sorted3.Keys())
+	})
+
+	t.Run("SortByValueFunc", func(t *testing.T) {
+		m := map[string][]int{"a": {1, 2, 3}, "b": {1}, "c": {1, 2}}
+		sorted := SortByValueFunc(m, func(a, b []int) int { return len(a) - len(b) })
+		assert.Equal(t, []string{"b", "c", "a"}, sorted.Keys())
+	})
+
+	t.Run("SortByValueKeyFunc", func(t *testing.T) {
+		m := map[string]int{"d": 1, "b": 1, "a": 1, "c": 0, "e": 2}
+		for range 10 {
+			sorted := SortByValueKeyFunc(m, cmp.Compare[int], strings.Compare)
+			assert.Equal(t, []string{"c", "a", "b", "d", "e"}, sorted.Keys())
+		}
 	})
 
 	t.Run("Sort", func(t *testing.T) {
 		m := map[string]string{"c": "a", "b": "b", "a": "c"}
-		assert.Equal(t, map[string]string{"a": "c", "b": "b", "c": "a"}, Sort(m))
+		sorted := Sort(m)
+		assert.Equal(t, []string{"a", "b", "c"}, sorted.Keys())
+		assert.Equal(t, []string{"c", "b", "a"}, sorted.Values())
 
 		m2 := map[int]int{1: 3, 2: 1, 3: 2}
-		assert.Equal(t, map[int]int{1: 3, 2: 1, 3: 2}, Sort(m2))
+		sorted2 := Sort(m2)
+		assert.Equal(t, []int{1, 2, 3}, sorted2.Keys())
+		assert.Equal(t, []int{3, 1, 2}, sorted2.Values())
+
+		sorted2.Set(0, 0)
+		assert.Equal(t, []int{0, 1, 2, 3}, sorted2.Keys())
+
+		m3 := map[string]int{"b": 2, "a": 1}
+		sorted3 := Sort(m3)
+		assert.Equal(t, []string{"a", "b"}, sorted3.Keys())
+		assert.Equal(t, []int{1, 2}, sorted3.Values())
 	})
 }
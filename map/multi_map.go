@@ -0,0 +1,411 @@
+package m
+
+import (
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/neurocode-io/go-pkgs/set"
+)
+
+// valueCollection holds the values of a MultiMap key.
+type valueCollection[V comparable] interface {
+	add(value V) bool
+	remove(value V) bool
+	contains(value V) bool
+	len() int
+	values() []V
+}
+
+// sliceValues keeps values in insertion order and allows duplicates.
+type sliceValues[V comparable] struct {
+	items []V
+}
+
+func (s *sliceValues[V]) add(value V) bool {
+	s.items = append(s.items, value)
+
+	return true
+}
+
+func (s *sliceValues[V]) remove(value V) bool {
+	i := slices.Index(s.items, value)
+	if i < 0 {
+		return false
+	}
+
+	s.items = slices.Delete(s.items, i, i+1)
+
+	return true
+}
+
+func (s *sliceValues[V]) contains(value V) bool {
+	return slices.Contains(s.items, value)
+}
+
+func (s *sliceValues[V]) len() int {
+	return len(s.items)
+}
+
+func (s *sliceValues[V]) values() []V {
+	return slices.Clone(s.items)
+}
+
+// setValues keeps distinct values in no particular order.
+type setValues[V comparable] struct {
+	items set.Set[V]
+}
+
+func (s *setValues[V]) add(value V) bool {
+	if s.items.Exists(value) {
+		return false
+	}
+
+	s.items.Add(value)
+
+	return true
+}
+
+func (s *setValues[V]) remove(value V) bool {
+	if !s.items.Exists(value) {
+		return false
+	}
+
+	s.items.Remove(value)
+
+	return true
+}
+
+func (s *setValues[V]) contains(value V) bool {
+	return s.items.Exists(value)
+}
+
+func (s *setValues[V]) len() int {
+	return s.items.Size()
+}
+
+func (s *setValues[V]) values() []V {
+	return s.items.ToSlice()
+}
+
+/*
+MultiMap is a map from a key to several values. It is not threadsafe; use SyncMultiMap for concurrent access.
+
+Example
+
+	tags := m.NewSetMultiMap[string, string]()
+	tags.Put("post-1", "go")
+	tags.Put("post-1", "go")
+	tags.GetAll("post-1") // []string{"go"}
+*/
+type MultiMap[K, V comparable] struct {
+	items     map[K]valueCollection[V]
+	newValues func() valueCollection[V]
+	size      int
+}
+
+/*
+NewMultiMap returns a new pointer to a MultiMap whose values are kept per key in insertion order, duplicates included.
+
+Example
+
+	events := m.NewMultiMap[string, string]()
+	events.Put("user-1", "login")
+	events.Put("user-1", "login")
+	events.GetAll("user-1") // []string{"login", "login"}
+*/
+func NewMultiMap[K, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		items:     make(map[K]valueCollection[V]),
+		newValues: func() valueCollection[V] { return &sliceValues[V]{} },
+	}
+}
+
+/*
+NewSetMultiMap returns a new pointer to a MultiMap whose values are distinct per key and kept in no particular order.
+
+Example
+
+	tags := m.NewSetMultiMap[string, string]()
+	tags.Put("post-1", "go") // true
+	tags.Put("post-1", "go") // false
+*/
+func NewSetMultiMap[K, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		items:     make(map[K]valueCollection[V]),
+		newValues: func() valueCollection[V] { return &setValues[V]{items: set.New[V]()} },
+	}
+}
+
+/*
+Put adds a value for a key. It returns false if the map is set-backed and the key already has the value.
+
+Example
+
+	tags.Put("post-1", "go")
+*/
+func (m *MultiMap[K, V]) Put(key K, value V) bool {
+	values, ok := m.items[key]
+	if !ok {
+		values = m.newValues()
+		m.items[key] = values
+	}
+
+	if !values.add(value) {
+		return false
+	}
+
+	m.size++
+
+	return true
+}
+
+/*
+PutAll adds several values for a key.
+
+Example
+
+	tags.PutAll("post-1", "go", "generics")
+*/
+func (m *MultiMap[K, V]) PutAll(key K, values ...V) {
+	for _, value := range values {
+		m.Put(key, value)
+	}
+}
+
+/*
+GetAll returns a copy of the values of a key, or nil if the key is not present.
+
+Example
+
+	tags.GetAll("post-1") // []string{"go", "generics"}
+*/
+func (m *MultiMap[K, V]) GetAll(key K) []V {
+	values, ok := m.items[key]
+	if !ok {
+		return nil
+	}
+
+	return values.values()
+}
+
+/*
+Has returns true if the key has at least one value.
+
+Example
+
+	tags.Has("post-1") // true
+*/
+func (m *MultiMap[K, V]) Has(key K) bool {
+	_, ok := m.items[key]
+
+	return ok
+}
+
+/*
+Contains returns true if the key has the value.
+
+Example
+
+	tags.Contains("post-1", "go") // true
+*/
+func (m *MultiMap[K, V]) Contains(key K, value V) bool {
+	values, ok := m.items[key]
+
+	return ok && values.contains(value)
+}
+
+/*
+Remove removes one occurrence of a value from a key and returns whether it was present. A key without values is deleted.
+
+Example
+
+	tags.Remove("post-1", "go") // true
+*/
+func (m *MultiMap[K, V]) Remove(key K, value V) bool {
+	values, ok := m.items[key]
+	if !ok || !values.remove(value) {
+		return false
+	}
+
+	m.size--
+
+	if values.len() == 0 {
+		delete(m.items, key)
+	}
+
+	return true
+}
+
+/*
+RemoveAll deletes a key and returns its values.
+
+Example
+
+	tags.RemoveAll("post-1") // []string{"go", "generics"}
+*/
+func (m *MultiMap[K, V]) RemoveAll(key K) []V {
+	values, ok := m.items[key]
+	if !ok {
+		return nil
+	}
+
+	delete(m.items, key)
+	m.size -= values.len()
+
+	return values.values()
+}
+
+/*
+Len returns the number of key and value pairs.
+
+Example
+
+	tags.PutAll("post-1", "go", "generics")
+	tags.Len() // 2
+*/
+func (m *MultiMap[K, V]) Len() int {
+	return m.size
+}
+
+/*
+Keys returns the keys that have at least one value.
+
+Example
+
+	tags.Keys() // []string{"post-1"}
+*/
+func (m *MultiMap[K, V]) Keys() []K {
+	return Keys(m.items)
+}
+
+/*
+All returns an iterator over the key and value pairs.
+
+Example
+
+	for key, value := range tags.All() {
+		fmt.Printf("%s: %s", key, value)
+	}
+*/
+func (m *MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, values := range m.items {
+			for _, v := range values.values() {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+/*
+SyncMultiMap is a threadsafe MultiMap.
+
+Example
+
+	tags := m.NewSyncSetMultiMap[string, string]()
+	tags.Put("post-1", "go")
+*/
+type SyncMultiMap[K, V comparable] struct {
+	items *MultiMap[K, V]
+	mutex sync.RWMutex
+}
+
+// NewSyncMultiMap returns a new pointer to a SyncMultiMap whose values are kept per key in insertion order.
+func NewSyncMultiMap[K, V comparable]() *SyncMultiMap[K, V] {
+	return &SyncMultiMap[K, V]{items: NewMultiMap[K, V]()}
+}
+
+// NewSyncSetMultiMap returns a new pointer to a SyncMultiMap whose values are distinct per key.
+func NewSyncSetMultiMap[K, V comparable]() *SyncMultiMap[K, V] {
+	return &SyncMultiMap[K, V]{items: NewSetMultiMap[K, V]()}
+}
+
+// Put adds a value for a key. It returns false if the map is set-backed and the key already has the value.
+func (m *SyncMultiMap[K, V]) Put(key K, value V) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.items.Put(key, value)
+}
+
+// PutAll adds several values for a key.
+func (m *SyncMultiMap[K, V]) PutAll(key K, values ...V) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.items.PutAll(key, values...)
+}
+
+// GetAll returns a copy of the values of a key, or nil if the key is not present.
+func (m *SyncMultiMap[K, V]) GetAll(key K) []V {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.items.GetAll(key)
+}
+
+// Has returns true if the key has at least one value.
+func (m *SyncMultiMap[K, V]) Has(key K) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.items.Has(key)
+}
+
+// Contains returns true if the key has the value.
+func (m *SyncMultiMap[K, V]) Contains(key K, value V) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.items.Contains(key, value)
+}
+
+// Remove removes one occurrence of a value from a key and returns whether it was present.
+func (m *SyncMultiMap[K, V]) Remove(key K, value V) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.items.Remove(key, value)
+}
+
+// RemoveAll deletes a key and returns its values.
+func (m *SyncMultiMap[K, V]) RemoveAll(key K) []V {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.items.RemoveAll(key)
+}
+
+// Len returns the number of key and value pairs.
+func (m *SyncMultiMap[K, V]) Len() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.items.Len()
+}
+
+// Keys returns the keys that have at least one value.
+func (m *SyncMultiMap[K, V]) Keys() []K {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.items.Keys()
+}
+
+// All returns an iterator over a snapshot of the key and value pairs, so the loop body may modify the map.
+func (m *SyncMultiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.mutex.RLock()
+		entries := CollectEntries(m.items.All())
+		m.mutex.RUnlock()
+
+		for _, entry := range entries {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
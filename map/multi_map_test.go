@@ -0,0 +1,132 @@
+package m
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiMap(t *testing.T) {
+	t.Run("slice-backed keeps order and duplicates", func(t *testing.T) {
+		mm := NewMultiMap[string, string]()
+		assert.True(t, mm.Put("user-1", "login"))
+		assert.True(t, mm.Put("user-1", "login"))
+		mm.PutAll("user-1", "logout")
+
+		assert.Equal(t, []string{"login", "login", "logout"}, mm.GetAll("user-1"))
+		assert.Equal(t, 3, mm.Len())
+
+		assert.True(t, mm.Remove("user-1", "login"))
+		assert.Equal(t, []string{"login", "logout"}, mm.GetAll("user-1"))
+		assert.Equal(t, 2, mm.Len())
+	})
+
+	t.Run("set-backed dedupes", func(t *testing.T) {
+		mm := NewSetMultiMap[string, string]()
+		assert.True(t, mm.Put("post-1", "go"))
+		assert.False(t, mm.Put("post-1", "go"))
+		mm.PutAll("post-1", "generics", "go")
+
+		assert.ElementsMatch(t, []string{"go", "generics"}, mm.GetAll("post-1"))
+		assert.Equal(t, 2, mm.Len())
+	})
+
+	t.Run("Has, Contains and Keys", func(t *testing.T) {
+		mm := NewMultiMap[string, int]()
+		mm.PutAll("a", 1, 2)
+		mm.Put("b", 3)
+
+		assert.True(t, mm.Has("a"))
+		assert.False(t, mm.Has("c"))
+		assert.True(t, mm.Contains("a", 2))
+		assert.False(t, mm.Contains("a", 3))
+		assert.False(t, mm.Contains("c", 1))
+		assert.ElementsMatch(t, []string{"a", "b"}, mm.Keys())
+		assert.Nil(t, mm.GetAll("c"))
+	})
+
+	t.Run("removing the last value deletes the key", func(t *testing.T) {
+		for _, mm := range []*MultiMap[string, int]{NewMultiMap[string, int](), NewSetMultiMap[string, int]()} {
+			mm.Put("a", 1)
+
+			assert.False(t, mm.Remove("a", 2))
+			assert.False(t, mm.Remove("b", 1))
+			assert.True(t, mm.Remove("a", 1))
+			assert.False(t, mm.Has("a"))
+			assert.Equal(t, 0, mm.Len())
+		}
+	})
+
+	t.Run("RemoveAll", func(t *testing.T) {
+		mm := NewMultiMap[string, int]()
+		mm.PutAll("a", 1, 2)
+		mm.Put("b", 3)
+
+		assert.Equal(t, []int{1, 2}, mm.RemoveAll("a"))
+		assert.Nil(t, mm.RemoveAll("a"))
+		assert.Equal(t, 1, mm.Len())
+	})
+
+	t.Run("GetAll returns a copy", func(t *testing.T) {
+		mm := NewMultiMap[string, int]()
+		mm.PutAll("a", 1, 2)
+		mm.GetAll("a")[0] = 42
+
+		assert.Equal(t, []int{1, 2}, mm.GetAll("a"))
+	})
+
+	t.Run("All", func(t *testing.T) {
+		mm := NewMultiMap[string, int]()
+		mm.PutAll("a", 1, 2)
+		mm.Put("b", 3)
+
+		assert.ElementsMatch(t, []Entry[string, int]{{"a", 1}, {"a", 2}, {"b", 3}}, CollectEntries(mm.All()))
+
+		for range mm.All() {
+			break
+		}
+	})
+}
+
+func TestSyncMultiMap(t *testing.T) {
+	t.Run("concurrent puts", func(t *testing.T) {
+		mm := NewSyncSetMultiMap[string, int]()
+
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for j := range 100 {
+					mm.Put("a", j)
+					mm.Put("b", i)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 110, mm.Len())
+		assert.Len(t, mm.GetAll("a"), 100)
+		assert.Len(t, mm.GetAll("b"), 10)
+	})
+
+	t.Run("methods", func(t *testing.T) {
+		mm := NewSyncMultiMap[string, int]()
+		mm.PutAll("a", 1, 1)
+
+		assert.True(t, mm.Has("a"))
+		assert.True(t, mm.Contains("a", 1))
+		assert.Equal(t, []string{"a"}, mm.Keys())
+		assert.True(t, mm.Remove("a", 1))
+		assert.Equal(t, []int{1}, mm.GetAll("a"))
+
+		for key := range mm.All() {
+			mm.Put(key, 2)
+		}
+
+		assert.Equal(t, []int{1, 2}, mm.RemoveAll("a"))
+		assert.Equal(t, 0, mm.Len())
+	})
+}
@@ -0,0 +1,273 @@
+package m
+
+import (
+	"iter"
+	"slices"
+)
+
+/*
+OrderedMap is a map that remembers the order of its keys. Keys are kept either in insertion order or,
+if the map was created with NewOrderedMapFunc, in the order defined by a comparator.
+In insertion order, Set and Delete take amortized O(1). In comparator order, the keys are kept in a sorted slice, so
+adding or deleting a key takes O(n) to shift the keys after it; use SortedMap when that matters.
+It is not safe for concurrent use.
+
+Example
+
+	om := m.NewOrderedMap[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+	om.Keys() // []string{"b", "a"}
+*/
+type OrderedMap[K comparable, V any] struct {
+	items map[K]orderedEntry[V]
+	cmp   func(a, b K) int
+	// keys holds the keys in order. In insertion order, deleted keys are left behind as dead slots until they make up
+	// half of the slice; a slot is live if the entry of its key points back at it.
+	keys []K
+	dead int
+}
+
+type orderedEntry[V any] struct {
+	value V
+	// index is the position of the key in keys. It is only maintained in insertion order.
+	index int
+}
+
+// NewOrderedMap returns a new pointer to an OrderedMap that keeps its keys in insertion order.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{items: make(map[K]orderedEntry[V])}
+}
+
+/*
+NewOrderedMapFunc returns a new pointer to an OrderedMap that keeps its keys sorted by cmp.
+cmp returns a negative number when a < b, a positive number when a > b and zero only when a == b.
+
+Example
+
+	om := m.NewOrderedMapFunc[string, int](strings.Compare)
+	om.Set("b", 2)
+	om.Set("a", 1)
+	om.Keys() // []string{"a", "b"}
+*/
+func NewOrderedMapFunc[K comparable, V any](cmp func(a, b K) int) *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{items: make(map[K]orderedEntry[V]), cmp: cmp}
+}
+
+// orderedMapFromSorted returns an OrderedMap holding entries, which must be sorted by cmp and have distinct keys.
+// If cmp is nil, the map keeps its keys in insertion order.
+func orderedMapFromSorted[K comparable, V any](entries []Entry[K, V], cmp func(a, b K) int) *OrderedMap[K, V] {
+	om := &OrderedMap[K, V]{items: make(map[K]orderedEntry[V], len(entries)), cmp: cmp, keys: make([]K, len(entries))}
+	for i, entry := range entries {
+		om.keys[i] = entry.Key
+		om.items[entry.Key] = orderedEntry[V]{value: entry.Value, index: i}
+	}
+
+	return om
+}
+
+/*
+Set sets the value for a key. Setting an existing key keeps its position.
+
+Example
+
+	om := m.NewOrderedMap[string, int]()
+	om.Set("a", 1)
+*/
+func (om *OrderedMap[K, V]) Set(key K, value V) {
+	entry, ok := om.items[key]
+	if !ok {
+		entry.index = om.insertKey(key)
+	}
+
+	entry.value = value
+	om.items[key] = entry
+}
+
+/*
+Get returns the value and true if the key exists in the map, otherwise it returns the zero value and false.
+
+Example
+
+	om := m.NewOrderedMap[string, int]()
+	om.Set("a", 1)
+	om.Get("a") // 1, true
+	om.Get("b") // 0, false
+*/
+func (om *OrderedMap[K, V]) Get(key K) (V, bool) {
+	entry, ok := om.items[key]
+
+	return entry.value, ok
+}
+
+// Has returns true if the key exists in the map.
+func (om *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := om.items[key]
+
+	return ok
+}
+
+/*
+Delete deletes the value for a key. If the key does not exist, it does nothing.
+
+Example
+
+	om := m.NewOrderedMap[string, int]()
+	om.Set("a", 1)
+	om.Delete("a")
+*/
+func (om *OrderedMap[K, V]) Delete(key K) {
+	entry, ok := om.items[key]
+	if !ok {
+		return
+	}
+
+	delete(om.items, key)
+
+	if om.cmp != nil {
+		i, _ := slices.BinarySearchFunc(om.keys, key, om.cmp)
+		om.keys = slices.Delete(om.keys, i, i+1)
+
+		return
+	}
+
+	var zero K
+
+	om.keys[entry.index] = zero
+	om.dead++
+
+	if om.dead*2 >= len(om.keys) {
+		om.compact()
+	}
+}
+
+// Len returns the number of keys in the map.
+func (om *OrderedMap[K, V]) Len() int {
+	return len(om.items)
+}
+
+/*
+Keys returns a slice of the keys in order.
+
+Example
+
+	om := m.NewOrderedMap[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+	om.Keys() // []string{"b", "a"}
+*/
+func (om *OrderedMap[K, V]) Keys() []K {
+	result := make([]K, 0, len(om.items))
+	for k := range om.All() {
+		result = append(result, k)
+	}
+
+	return result
+}
+
+/*
+Values returns a slice of the values in the order of their keys.
+
+Example
+
+	om := m.NewOrderedMap[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+	om.Values() // []int{2, 1}
+*/
+func (om *OrderedMap[K, V]) Values() []V {
+	result := make([]V, 0, len(om.items))
+	for _, v := range om.All() {
+		result = append(result, v)
+	}
+
+	return result
+}
+
+/*
+Entries returns a slice of the entries in order.
+
+Example
+
+	om := m.NewOrderedMap[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+	om.Entries() // []m.Entry[string, int]{{"b", 2}, {"a", 1}}
+*/
+func (om *OrderedMap[K, V]) Entries() []Entry[K, V] {
+	result := make([]Entry[K, V], 0, len(om.items))
+	for k, v := range om.All() {
+		result = append(result, Entry[K, V]{Key: k, Value: v})
+	}
+
+	return result
+}
+
+/*
+Range calls fn sequentially for each key and value in order. If fn returns false, range stops the iteration.
+
+Example
+
+	om.Range(func(key string, value int) bool {
+		fmt.Printf("%s: %d", key, value)
+		return true
+	})
+*/
+func (om *OrderedMap[K, V]) Range(fn func(key K, value V) bool) {
+	for i, k := range om.keys {
+		entry, ok := om.items[k]
+		if !ok || om.cmp == nil && entry.index != i {
+			continue
+		}
+
+		if !fn(k, entry.value) {
+			return
+		}
+	}
+}
+
+/*
+All returns an iterator over the keys and values in order.
+
+Example
+
+	om := m.NewOrderedMap[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+	for key, value := range om.All() {
+		fmt.Printf("%s: %d", key, value) // b: 2, then a: 1
+	}
+*/
+func (om *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return om.Range
+}
+
+// insertKey adds a new key to keys and returns its index.
+func (om *OrderedMap[K, V]) insertKey(key K) int {
+	if om.cmp == nil {
+		om.keys = append(om.keys, key)
+
+		return len(om.keys) - 1
+	}
+
+	i, _ := slices.BinarySearchFunc(om.keys, key, om.cmp)
+	om.keys = slices.Insert(om.keys, i, key)
+
+	return i
+}
+
+// compact drops the dead slots of keys in insertion order.
+func (om *OrderedMap[K, V]) compact() {
+	live := om.keys[:0]
+	for i, k := range om.keys {
+		if entry, ok := om.items[k]; ok && entry.index == i {
+			entry.index = len(live)
+			om.items[k] = entry
+			live = append(live, k)
+		}
+	}
+
+	clear(om.keys[len(live):])
+	om.keys = live
+	om.dead = 0
+}
@@ -0,0 +1,155 @@
+package m
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedMap(t *testing.T) {
+	t.Run("insertion order", func(t *testing.T) {
+		om := NewOrderedMap[string, int]()
+		om.Set("c", 3)
+		om.Set("a", 1)
+		om.Set("b", 2)
+		om.Set("c", 30)
+
+		assert.Equal(t, 3, om.Len())
+		assert.Equal(t, []string{"c", "a", "b"}, om.Keys())
+		assert.Equal(t, []int{30, 1, 2}, om.Values())
+		assert.Equal(t, []Entry[string, int]{{"c", 30}, {"a", 1}, {"b", 2}}, om.Entries())
+	})
+
+	t.Run("comparator order", func(t *testing.T) {
+		om := NewOrderedMapFunc[string, int](strings.Compare)
+		om.Set("c", 3)
+		om.Set("a", 1)
+		om.Set("b", 2)
+
+		assert.Equal(t, []string{"a", "b", "c"}, om.Keys())
+		assert.Equal(t, []int{1, 2, 3}, om.Values())
+
+		om.Delete("b")
+		assert.Equal(t, []string{"a", "c"}, om.Keys())
+	})
+
+	t.Run("Get/Has", func(t *testing.T) {
+		om := NewOrderedMap[string, int]()
+		om.Set("a", 1)
+
+		v, ok := om.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+		assert.True(t, om.Has("a"))
+
+		v, ok = om.Get("b")
+		assert.False(t, ok)
+		assert.Equal(t, 0, v)
+		assert.False(t, om.Has("b"))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		om := NewOrderedMap[string, int]()
+		om.Set("a", 1)
+		om.Set("b", 2)
+		om.Set("c", 3)
+		om.Delete("b")
+		om.Delete("d")
+
+		assert.Equal(t, []string{"a", "c"}, om.Keys())
+		assert.False(t, om.Has("b"))
+
+		om.Set("b", 4)
+		assert.Equal(t, []string{"a", "c", "b"}, om.Keys())
+	})
+
+	t.Run("matches a builtin map under many deletes", func(t *testing.T) {
+		for name, om := range map[string]*OrderedMap[int, int]{
+			"insertion":  NewOrderedMap[int, int](),
+			"comparator": NewOrderedMapFunc[int, int](func(a, b int) int { return b - a }),
+		} {
+			t.Run(name, func(t *testing.T) {
+				rnd := rand.New(rand.NewPCG(7, 8))
+				expected := map[int]int{}
+				var order []int
+
+				for i := range 3000 {
+					key := rnd.IntN(200)
+					if rnd.IntN(2) == 0 {
+						delete(expected, key)
+						om.Delete(key)
+						order = slices.DeleteFunc(order, func(k int) bool { return k == key })
+
+						continue
+					}
+
+					if _, ok := expected[key]; !ok {
+						order = append(order, key)
+					}
+
+					expected[key] = i
+					om.Set(key, i)
+				}
+
+				if name == "comparator" {
+					slices.SortFunc(order, func(a, b int) int { return b - a })
+				}
+
+				assert.Equal(t, order, om.Keys())
+				assert.Equal(t, expected, Collect(om.All()))
+				assert.Equal(t, len(expected), om.Len())
+			})
+		}
+	})
+
+	t.Run("zero keys survive compaction", func(t *testing.T) {
+		om := NewOrderedMap[string, int]()
+		om.Set("", 0)
+		for i := range 10 {
+			om.Set(strconv.Itoa(i), i)
+		}
+
+		for i := range 10 {
+			om.Delete(strconv.Itoa(i))
+		}
+
+		om.Set("a", 1)
+		assert.Equal(t, []string{"", "a"}, om.Keys())
+		assert.Equal(t, []int{0, 1}, om.Values())
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		om := NewOrderedMap[string, int]()
+		om.Set("b", 2)
+		om.Set("a", 1)
+
+		var keys []string
+		om.Range(func(key string, _ int) bool {
+			keys = append(keys, key)
+
+			return true
+		})
+		assert.Equal(t, []string{"b", "a"}, keys)
+
+		runs := 0
+		om.Range(func(string, int) bool {
+			runs++
+
+			return false
+		})
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("Keys returns a copy", func(t *testing.T) {
+		om := NewOrderedMap[string, int]()
+		om.Set("a", 1)
+		keys := om.Keys()
+		keys[0] = "z"
+
+		assert.Equal(t, []string{"a"}, om.Keys())
+	})
+}
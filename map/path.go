@@ -0,0 +1,479 @@
+package m
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrPathNotFound is returned when a path does not exist in a Tree.
+	ErrPathNotFound = errors.New("path not found")
+	// ErrInvalidPath is returned when a path is empty or goes through a value that is neither a map nor a slice.
+	ErrInvalidPath = errors.New("invalid path")
+	// ErrTypeMismatch is returned by the typed getters of a Tree when a value cannot be converted.
+	ErrTypeMismatch = errors.New("type mismatch")
+)
+
+/*
+Tree is a nested map[string]any tree, such as the ones produced by structs.ToMap or by decoding JSON into a map.
+Its methods address values with paths, either dotted ("db.hosts.0") or JSON pointers ("/db/hosts/0").
+Slice elements are addressed by index; when setting, the index may be the length of the slice, or "-", to append.
+
+Example
+
+	tree := m.Tree(structs.ToMap(config).(map[string]any))
+	host, err := tree.GetString("db.hosts.0")
+	err = tree.Set("/db/port", 5432)
+*/
+type Tree map[string]any
+
+/*
+Get returns the value at the path.
+
+Example
+
+	tree := m.Tree{"db": map[string]any{"hosts": []any{"a", "b"}}}
+	tree.Get("db.hosts.1")  // "b", true
+	tree.Get("/db/hosts/1") // "b", true
+	tree.Get("db.port")     // nil, false
+*/
+func (t Tree) Get(path string) (any, bool) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var node any = map[string]any(t)
+	for _, segment := range segments {
+		switch n := node.(type) {
+		case map[string]any:
+			v, ok := n[segment]
+			if !ok {
+				return nil, false
+			}
+
+			node = v
+		case Tree:
+			v, ok := n[segment]
+			if !ok {
+				return nil, false
+			}
+
+			node = v
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, false
+			}
+
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return node, true
+}
+
+/*
+Has returns true if the path exists in the tree.
+
+Example
+
+	tree := m.Tree{"db": map[string]any{"port": nil}}
+	tree.Has("db.port") // true
+*/
+func (t Tree) Has(path string) bool {
+	_, ok := t.Get(path)
+
+	return ok
+}
+
+/*
+Set sets the value at the path, creating the missing maps along the way. It returns ErrInvalidPath if the path goes
+through a value that is neither a map nor a slice, or through a slice index out of range. The tree is left unchanged
+on error.
+
+Example
+
+	tree := m.Tree{}
+	tree.Set("db.port", 5432)
+	// tree == m.Tree{"db": map[string]any{"port": 5432}}
+*/
+func (t Tree) Set(path string, value any) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = setPath(map[string]any(t), segments, value)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, path)
+	}
+
+	return nil
+}
+
+/*
+Delete deletes the value at the path and returns whether it existed. Deleting a slice element shifts the following
+elements; the slice is copied, so other references to it are not affected.
+
+Example
+
+	tree := m.Tree{"hosts": []any{"a", "b", "c"}}
+	tree.Delete("hosts.0") // true
+	// tree == m.Tree{"hosts": []any{"b", "c"}}
+*/
+func (t Tree) Delete(path string) bool {
+	segments, err := parsePath(path)
+	if err != nil {
+		return false
+	}
+
+	_, deleted := deletePath(map[string]any(t), segments)
+
+	return deleted
+}
+
+/*
+GetString returns the string at the path. Values implementing fmt.Stringer are converted.
+
+Example
+
+	host, err := tree.GetString("db.host")
+*/
+func (t Tree) GetString(path string) (string, error) {
+	v, err := t.lookup(path)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	default:
+		return "", mismatch(path, v, "string")
+	}
+}
+
+/*
+GetInt returns the integer at the path. Integers of any size, floats without a fractional part, json.Number and
+numeric strings are converted.
+
+Example
+
+	tree := m.Tree{"port": "5432"}
+	tree.GetInt("port") // 5432, nil
+*/
+func (t Tree) GetInt(path string) (int, error) {
+	v, err := t.lookup(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if s, ok := v.(string); ok {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s: %w", ErrTypeMismatch, path, err)
+		}
+
+		return i, nil
+	}
+
+	if n, ok := v.(json.Number); ok {
+		i, err := n.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s: %w", ErrTypeMismatch, path, err)
+		}
+
+		v = i
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := rv.Int(); i >= math.MinInt && i <= math.MaxInt {
+			return int(i), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= math.MaxInt {
+			return int(u), nil //nolint:gosec
+		}
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); f == math.Trunc(f) && f >= math.MinInt && f < math.MaxInt {
+			return int(f), nil
+		}
+	}
+
+	return 0, mismatch(path, v, "int")
+}
+
+/*
+GetFloat returns the number at the path as a float64. Integers, json.Number and numeric strings are converted.
+
+Example
+
+	tree := m.Tree{"ratio": 0.5}
+	tree.GetFloat("ratio") // 0.5, nil
+*/
+func (t Tree) GetFloat(path string) (float64, error) {
+	v, err := t.lookup(path)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n := v.(type) {
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s: %w", ErrTypeMismatch, path, err)
+		}
+
+		return f, nil
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s: %w", ErrTypeMismatch, path, err)
+		}
+
+		return f, nil
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	default:
+		return 0, mismatch(path, v, "float64")
+	}
+}
+
+/*
+GetBool returns the boolean at the path. Strings accepted by strconv.ParseBool are converted.
+
+Example
+
+	tree := m.Tree{"debug": "true"}
+	tree.GetBool("debug") // true, nil
+*/
+func (t Tree) GetBool(path string) (bool, error) {
+	v, err := t.lookup(path)
+	if err != nil {
+		return false, err
+	}
+
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		parsed, err := strconv.ParseBool(b)
+		if err != nil {
+			return false, fmt.Errorf("%w: %s: %w", ErrTypeMismatch, path, err)
+		}
+
+		return parsed, nil
+	default:
+		return false, mismatch(path, v, "bool")
+	}
+}
+
+/*
+GetTree returns the map at the path as a Tree. The returned Tree shares its entries with t.
+
+Example
+
+	db, err := tree.GetTree("db")
+	db.GetString("host")
+*/
+func (t Tree) GetTree(path string) (Tree, error) {
+	v, err := t.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n := v.(type) {
+	case map[string]any:
+		return n, nil
+	case Tree:
+		return n, nil
+	default:
+		return nil, mismatch(path, v, "map")
+	}
+}
+
+/*
+GetSlice returns the slice at the path.
+
+Example
+
+	hosts, err := tree.GetSlice("db.hosts")
+*/
+func (t Tree) GetSlice(path string) ([]any, error) {
+	v, err := t.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := v.([]any)
+	if !ok {
+		return nil, mismatch(path, v, "slice")
+	}
+
+	return s, nil
+}
+
+func (t Tree) lookup(path string) (any, error) {
+	if _, err := parsePath(path); err != nil {
+		return nil, err
+	}
+
+	v, ok := t.Get(path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	}
+
+	return v, nil
+}
+
+func mismatch(path string, v any, want string) error {
+	return fmt.Errorf("%w: %s is %T, not %s", ErrTypeMismatch, path, v, want)
+}
+
+// parsePath splits a dotted path or a JSON pointer into its segments.
+func parsePath(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: empty path", ErrInvalidPath)
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return strings.Split(path, "."), nil
+	}
+
+	segments := strings.Split(path[1:], "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+
+	return segments, nil
+}
+
+// setPath sets value at segments below node and returns the updated node, which is a new slice when a slice grows.
+// Nothing is modified if an error is returned.
+func setPath(node any, segments []string, value any) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	switch n := node.(type) {
+	case nil:
+		child, err := setPath(nil, rest, value)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{segment: child}, nil
+	case map[string]any:
+		child, err := setPath(n[segment], rest, value)
+		if err != nil {
+			return nil, err
+		}
+
+		n[segment] = child
+
+		return n, nil
+	case Tree:
+		if _, err := setPath(map[string]any(n), segments, value); err != nil {
+			return nil, err
+		}
+
+		return n, nil
+	case []any:
+		i := len(n)
+		if segment != "-" {
+			var err error
+			if i, err = strconv.Atoi(segment); err != nil || i < 0 || i > len(n) {
+				return nil, fmt.Errorf("%w: index %q out of range", ErrInvalidPath, segment)
+			}
+		}
+
+		var current any
+		if i < len(n) {
+			current = n[i]
+		}
+
+		child, err := setPath(current, rest, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == len(n) {
+			return append(n, child), nil
+		}
+
+		n[i] = child
+
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: cannot set %q in a %T", ErrInvalidPath, segment, node)
+	}
+}
+
+// deletePath deletes the value at segments below node and returns the updated node and whether a value was deleted.
+func deletePath(node any, segments []string) (any, bool) {
+	segment, rest := segments[0], segments[1:]
+
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[segment]
+		if !ok {
+			return n, false
+		}
+
+		if len(rest) == 0 {
+			delete(n, segment)
+
+			return n, true
+		}
+
+		child, deleted := deletePath(child, rest)
+		n[segment] = child
+
+		return n, deleted
+	case Tree:
+		_, deleted := deletePath(map[string]any(n), segments)
+
+		return n, deleted
+	case []any:
+		i, err := strconv.Atoi(segment)
+		if err != nil || i < 0 || i >= len(n) {
+			return n, false
+		}
+
+		if len(rest) == 0 {
+			return slices.Concat(n[:i], n[i+1:]), true
+		}
+
+		child, deleted := deletePath(n[i], rest)
+		n[i] = child
+
+		return n, deleted
+	default:
+		return node, false
+	}
+}
@@ -0,0 +1,218 @@
+package m
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/neurocode-io/go-pkgs/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	newTree := func() Tree {
+		return Tree{
+			"db": map[string]any{
+				"hosts": []any{"a", map[string]any{"name": "b"}},
+				"port":  5432,
+			},
+			"a/b": map[string]any{"~c": true},
+		}
+	}
+
+	t.Run("Get with dotted paths and JSON pointers", func(t *testing.T) {
+		tree := newTree()
+
+		for path, expected := range map[string]any{
+			"db.port":          5432,
+			"db.hosts.0":       "a",
+			"db.hosts.1.name":  "b",
+			"/db/hosts/1/name": "b",
+			"/a~1b/~0c":        true,
+		} {
+			v, ok := tree.Get(path)
+			assert.True(t, ok, path)
+			assert.Equal(t, expected, v, path)
+		}
+
+		for _, path := range []string{"", "db.user", "db.hosts.2", "db.hosts.-1", "db.hosts.x", "db.port.x", "/db/nope"} {
+			_, ok := tree.Get(path)
+			assert.False(t, ok, path)
+			assert.False(t, tree.Has(path), path)
+		}
+	})
+
+	t.Run("Get from structs.ToMap", func(t *testing.T) {
+		type DB struct {
+			Hosts []string
+		}
+
+		type Config struct {
+			DB DB
+		}
+
+		tree := Tree(structs.ToMap(Config{DB: DB{Hosts: []string{"a", "b"}}}).(map[string]any)) //nolint:errcheck
+
+		host, err := tree.GetString("DB.Hosts.1")
+		assert.NoError(t, err)
+		assert.Equal(t, "b", host)
+	})
+
+	t.Run("Set creates missing maps", func(t *testing.T) {
+		tree := Tree{}
+		assert.NoError(t, tree.Set("db.port", 5432))
+		assert.NoError(t, tree.Set("/db/user name", "app"))
+
+		assert.Equal(t, Tree{"db": map[string]any{"port": 5432, "user name": "app"}}, tree)
+	})
+
+	t.Run("Set in slices", func(t *testing.T) {
+		tree := newTree()
+		assert.NoError(t, tree.Set("db.hosts.0", "x"))
+		assert.NoError(t, tree.Set("db.hosts.2", "y"))
+		assert.NoError(t, tree.Set("/db/hosts/-", "z"))
+		assert.NoError(t, tree.Set("db.hosts.1.port", 1))
+		assert.NoError(t, tree.Set("db.hosts.-.name", "w"))
+
+		hosts, err := tree.GetSlice("db.hosts")
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"x", map[string]any{"name": "b", "port": 1}, "y", "z", map[string]any{"name": "w"}}, hosts)
+	})
+
+	t.Run("Set errors leave the tree unchanged", func(t *testing.T) {
+		tree := newTree()
+
+		assert.ErrorIs(t, tree.Set("db.port.x", 1), ErrInvalidPath)
+		assert.ErrorIs(t, tree.Set("db.hosts.5", 1), ErrInvalidPath)
+		assert.ErrorIs(t, tree.Set("db.hosts.x", 1), ErrInvalidPath)
+		assert.ErrorIs(t, tree.Set("", 1), ErrInvalidPath)
+		assert.Equal(t, newTree(), tree)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		tree := newTree()
+		hosts := tree["db"].(map[string]any)["hosts"] //nolint:errcheck
+
+		assert.True(t, tree.Delete("db.hosts.0"))
+		assert.True(t, tree.Delete("/a~1b/~0c"))
+		assert.True(t, tree.Delete("db.hosts.0.name"))
+		assert.False(t, tree.Delete("db.hosts.3"))
+		assert.False(t, tree.Delete("db.user"))
+		assert.False(t, tree.Delete("db.port.x"))
+		assert.False(t, tree.Delete(""))
+
+		assert.Equal(t, Tree{
+			"db":  map[string]any{"hosts": []any{map[string]any{}}, "port": 5432},
+			"a/b": map[string]any{},
+		}, tree)
+		assert.Equal(t, []any{"a", map[string]any{}}, hosts, "the original slice keeps its length")
+	})
+
+	t.Run("nested Trees", func(t *testing.T) {
+		tree := Tree{"db": Tree{"port": 1}}
+		assert.NoError(t, tree.Set("db.user", "app"))
+		assert.True(t, tree.Delete("db.port"))
+
+		db, err := tree.GetTree("db")
+		assert.NoError(t, err)
+		assert.Equal(t, Tree{"user": "app"}, db)
+		assert.Equal(t, Tree{"db": Tree{"user": "app"}}, tree)
+	})
+
+	t.Run("typed getters", func(t *testing.T) {
+		tree := Tree{
+			"string":   "s",
+			"stringer": time.Second,
+			"int":      int8(-3),
+			"uint":     uint64(7),
+			"float":    2.0,
+			"fraction": 2.5,
+			"number":   json.Number("12"),
+			"numeric":  "42",
+			"bool":     true,
+			"boolish":  "false",
+			"map":      map[string]any{"a": 1},
+			"slice":    []any{1},
+		}
+
+		s, err := tree.GetString("string")
+		assert.NoError(t, err)
+		assert.Equal(t, "s", s)
+
+		s, err = tree.GetString("stringer")
+		assert.NoError(t, err)
+		assert.Equal(t, "1s", s)
+
+		for path, expected := range map[string]int{"int": -3, "uint": 7, "float": 2, "number": 12, "numeric": 42} {
+			i, err := tree.GetInt(path)
+			assert.NoError(t, err, path)
+			assert.Equal(t, expected, i, path)
+		}
+
+		for path, expected := range map[string]float64{"int": -3, "uint": 7, "fraction": 2.5, "number": 12, "numeric": 42} {
+			f, err := tree.GetFloat(path)
+			assert.NoError(t, err, path)
+			assert.InDelta(t, expected, f, 1e-9, path)
+		}
+
+		b, err := tree.GetBool("bool")
+		assert.NoError(t, err)
+		assert.True(t, b)
+
+		b, err = tree.GetBool("boolish")
+		assert.NoError(t, err)
+		assert.False(t, b)
+
+		sub, err := tree.GetTree("map")
+		assert.NoError(t, err)
+		assert.Equal(t, Tree{"a": 1}, sub)
+
+		slice, err := tree.GetSlice("slice")
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1}, slice)
+	})
+
+	t.Run("typed getter errors", func(t *testing.T) {
+		tree := Tree{"s": "abc", "f": 2.5, "n": json.Number("1.5"), "i": 1, "u": uint64(1 << 63)}
+
+		_, err := tree.GetString("missing")
+		assert.ErrorIs(t, err, ErrPathNotFound)
+
+		_, err = tree.GetString("")
+		assert.ErrorIs(t, err, ErrInvalidPath)
+
+		_, err = tree.GetString("i")
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+		assert.ErrorContains(t, err, "i is int, not string")
+
+		for _, path := range []string{"s", "f", "n", "u"} {
+			_, err = tree.GetInt(path)
+			assert.ErrorIs(t, err, ErrTypeMismatch, path)
+		}
+
+		_, err = tree.GetFloat("s")
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+		assert.ErrorContains(t, err, "invalid syntax")
+
+		_, err = tree.GetFloat("x")
+		assert.ErrorIs(t, err, ErrPathNotFound)
+
+		_, err = Tree{"b": []any{}}.GetFloat("b")
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+
+		_, err = Tree{"n": json.Number("x")}.GetFloat("n")
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+
+		_, err = tree.GetBool("s")
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+
+		_, err = tree.GetBool("i")
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+
+		_, err = tree.GetTree("i")
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+
+		_, err = tree.GetSlice("i")
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+	})
+}
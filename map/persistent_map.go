@@ -0,0 +1,668 @@
+package m
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+const (
+	hamtBits  = 5
+	hamtWidth = 1 << hamtBits
+	hamtMask  = hamtWidth - 1
+	// hamtMaxShift is the first shift past the 64 hash bits. Nodes at this depth hold full hash collisions.
+	hamtMaxShift = 65
+)
+
+// editToken marks the nodes owned by a PersistentBuilder, which may be modified in place. It is not zero-sized,
+// so that distinct tokens have distinct addresses.
+type editToken struct {
+	_ byte
+}
+
+type hamtLeaf[K comparable, V any] struct {
+	key   K
+	value V
+	hash  uint64
+}
+
+// hamtSlot is either a child node or, if child is nil, a leaf.
+type hamtSlot[K comparable, V any] struct {
+	child *hamtNode[K, V]
+	leaf  hamtLeaf[K, V]
+}
+
+// hamtNode is a bitmap-indexed node of a hash array mapped trie. Below hamtMaxShift, slots holds one slot per bit set
+// in bitmap. At hamtMaxShift, collisions holds the leaves whose 64-bit hashes are all equal.
+type hamtNode[K comparable, V any] struct {
+	edit       *editToken
+	slots      []hamtSlot[K, V]
+	collisions []hamtLeaf[K, V]
+	bitmap     uint32
+}
+
+func hamtIndex(bitmap uint32, hash uint64, shift uint) (bit uint32, index int) {
+	bit = 1 << ((hash >> shift) & hamtMask)
+
+	return bit, bits.OnesCount32(bitmap & (bit - 1))
+}
+
+// editable returns n if it is owned by edit, otherwise a copy owned by edit.
+func (n *hamtNode[K, V]) editable(edit *editToken) *hamtNode[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+
+	return &hamtNode[K, V]{
+		edit:       edit,
+		slots:      slices.Clone(n.slots),
+		collisions: slices.Clone(n.collisions),
+		bitmap:     n.bitmap,
+	}
+}
+
+// singleLeaf returns the only leaf of a node that has no other entries, so that its parent can inline it.
+func (n *hamtNode[K, V]) singleLeaf() (hamtLeaf[K, V], bool) {
+	if len(n.collisions) == 1 {
+		return n.collisions[0], true
+	}
+
+	if len(n.slots) == 1 && n.slots[0].child == nil {
+		return n.slots[0].leaf, true
+	}
+
+	return hamtLeaf[K, V]{}, false
+}
+
+func (n *hamtNode[K, V]) get(key K, hash uint64, shift uint) (value V, ok bool) {
+	for {
+		if shift >= hamtMaxShift {
+			for _, leaf := range n.collisions {
+				if leaf.key == key {
+					return leaf.value, true
+				}
+			}
+
+			return value, false
+		}
+
+		bit, i := hamtIndex(n.bitmap, hash, shift)
+		if n.bitmap&bit == 0 {
+			return value, false
+		}
+
+		slot := n.slots[i]
+		if slot.child == nil {
+			if slot.leaf.key == key {
+				return slot.leaf.value, true
+			}
+
+			return value, false
+		}
+
+		n = slot.child
+		shift += hamtBits
+	}
+}
+
+// set returns the node with the leaf stored and whether the key was added rather than replaced.
+func (n *hamtNode[K, V]) set(edit *editToken, leaf hamtLeaf[K, V], shift uint) (*hamtNode[K, V], bool) {
+	if shift >= hamtMaxShift {
+		result := n.editable(edit)
+
+		for i := range result.collisions {
+			if result.collisions[i].key == leaf.key {
+				result.collisions[i] = leaf
+
+				return result, false
+			}
+		}
+
+		result.collisions = append(result.collisions, leaf)
+
+		return result, true
+	}
+
+	bit, i := hamtIndex(n.bitmap, leaf.hash, shift)
+	if n.bitmap&bit == 0 {
+		result := n.editable(edit)
+		result.bitmap |= bit
+		result.slots = slices.Insert(result.slots, i, hamtSlot[K, V]{leaf: leaf})
+
+		return result, true
+	}
+
+	var (
+		slot  hamtSlot[K, V]
+		added bool
+	)
+
+	switch current := n.slots[i]; {
+	case current.child != nil:
+		slot.child, added = current.child.set(edit, leaf, shift+hamtBits)
+	case current.leaf.key == leaf.key:
+		slot.leaf = leaf
+	default:
+		slot.child = newHamtPair(edit, current.leaf, leaf, shift+hamtBits)
+		added = true
+	}
+
+	result := n.editable(edit)
+	result.slots[i] = slot
+
+	return result, added
+}
+
+// newHamtPair returns a node holding two leaves with different keys.
+func newHamtPair[K comparable, V any](edit *editToken, a, b hamtLeaf[K, V], shift uint) *hamtNode[K, V] {
+	if shift >= hamtMaxShift {
+		return &hamtNode[K, V]{edit: edit, collisions: []hamtLeaf[K, V]{a, b}}
+	}
+
+	bitA, _ := hamtIndex(0, a.hash, shift)
+	bitB, _ := hamtIndex(0, b.hash, shift)
+
+	switch {
+	case bitA == bitB:
+		child := newHamtPair(edit, a, b, shift+hamtBits)
+
+		return &hamtNode[K, V]{edit: edit, bitmap: bitA, slots: []hamtSlot[K, V]{{child: child}}}
+	case bitA < bitB:
+		return &hamtNode[K, V]{edit: edit, bitmap: bitA | bitB, slots: []hamtSlot[K, V]{{leaf: a}, {leaf: b}}}
+	default:
+		return &hamtNode[K, V]{edit: edit, bitmap: bitA | bitB, slots: []hamtSlot[K, V]{{leaf: b}, {leaf: a}}}
+	}
+}
+
+// delete returns the node without the key, nil if it became empty, and whether the key was removed.
+func (n *hamtNode[K, V]) delete(edit *editToken, key K, hash uint64, shift uint) (*hamtNode[K, V], bool) {
+	if shift >= hamtMaxShift {
+		i := slices.IndexFunc(n.collisions, func(leaf hamtLeaf[K, V]) bool { return leaf.key == key })
+		if i < 0 {
+			return n, false
+		}
+
+		if len(n.collisions) == 1 {
+			return nil, true
+		}
+
+		result := n.editable(edit)
+		result.collisions = slices.Delete(result.collisions, i, i+1)
+
+		return result, true
+	}
+
+	bit, i := hamtIndex(n.bitmap, hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	current := n.slots[i]
+
+	if current.child == nil {
+		if current.leaf.key != key {
+			return n, false
+		}
+
+		return n.withoutSlot(edit, bit, i), true
+	}
+
+	child, removed := current.child.delete(edit, key, hash, shift+hamtBits)
+	if !removed {
+		return n, false
+	}
+
+	if child == nil {
+		return n.withoutSlot(edit, bit, i), true
+	}
+
+	result := n.editable(edit)
+	if leaf, ok := child.singleLeaf(); ok {
+		result.slots[i] = hamtSlot[K, V]{leaf: leaf}
+	} else {
+		result.slots[i] = hamtSlot[K, V]{child: child}
+	}
+
+	return result, true
+}
+
+func (n *hamtNode[K, V]) withoutSlot(edit *editToken, bit uint32, i int) *hamtNode[K, V] {
+	if len(n.slots) == 1 {
+		return nil
+	}
+
+	result := n.editable(edit)
+	result.bitmap &^= bit
+	result.slots = slices.Delete(result.slots, i, i+1)
+
+	return result
+}
+
+func (n *hamtNode[K, V]) all(yield func(K, V) bool) bool {
+	for _, leaf := range n.collisions {
+		if !yield(leaf.key, leaf.value) {
+			return false
+		}
+	}
+
+	for _, slot := range n.slots {
+		if slot.child == nil {
+			if !yield(slot.leaf.key, slot.leaf.value) {
+				return false
+			}
+		} else if !slot.child.all(yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (n *hamtNode[K, V]) leaves() []hamtLeaf[K, V] {
+	result := slices.Clone(n.collisions)
+	for _, slot := range n.slots {
+		if slot.child == nil {
+			result = append(result, slot.leaf)
+		} else {
+			result = append(result, slot.child.leaves()...)
+		}
+	}
+
+	return result
+}
+
+// persistentConfig is shared by all the versions of a PersistentMap. Versions sharing it also share their hashes,
+// so that their tries can be compared node by node.
+type persistentConfig[K comparable] struct {
+	hasher func(key K) uint64
+}
+
+/*
+PersistentMap is an immutable map. Set and Delete return a new version in O(log n) that shares most of its structure
+with the previous one, so versions can be passed between goroutines without copying or locking.
+Use a PersistentBuilder for batches of edits.
+
+Example
+
+	v1 := m.NewPersistentMap[string, int](nil).Set("a", 1)
+	v2 := v1.Set("b", 2)
+	v1.Len() // 1
+	v2.Len() // 2
+*/
+type PersistentMap[K comparable, V any] struct {
+	config *persistentConfig[K]
+	root   *hamtNode[K, V]
+	size   int
+}
+
+/*
+NewPersistentMap returns a new pointer to an empty PersistentMap. Keys are hashed with hasher; if hasher is nil,
+the hasher returned by NewHasher is used.
+
+Example
+
+	empty := m.NewPersistentMap[string, int](nil)
+	byID := m.NewPersistentMap[User, int](func(u User) uint64 { return u.ID })
+*/
+func NewPersistentMap[K comparable, V any](hasher func(key K) uint64) *PersistentMap[K, V] {
+	if hasher == nil {
+		hasher = NewHasher[K]()
+	}
+
+	return &PersistentMap[K, V]{config: &persistentConfig[K]{hasher: hasher}}
+}
+
+/*
+Get returns the value and true if the key exists in the map, otherwise it returns the zero value and false.
+
+Example
+
+	pm := m.NewPersistentMap[string, int](nil).Set("a", 1)
+	pm.Get("a") // 1, true
+*/
+func (m *PersistentMap[K, V]) Get(key K) (value V, ok bool) {
+	if m.root == nil {
+		return value, false
+	}
+
+	return m.root.get(key, m.config.hasher(key), 0)
+}
+
+// Has returns true if the key exists in the map.
+func (m *PersistentMap[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+
+	return ok
+}
+
+// Len returns the number of entries.
+func (m *PersistentMap[K, V]) Len() int {
+	return m.size
+}
+
+/*
+Set returns a new version of the map with the value set for the key. The receiver is not modified.
+
+Example
+
+	v1 := m.NewPersistentMap[string, int](nil)
+	v2 := v1.Set("a", 1)
+	v1.Has("a") // false
+	v2.Has("a") // true
+*/
+func (m *PersistentMap[K, V]) Set(key K, value V) *PersistentMap[K, V] {
+	root, added := hamtSet(m.root, nil, hamtLeaf[K, V]{key: key, value: value, hash: m.config.hasher(key)})
+
+	result := &PersistentMap[K, V]{config: m.config, root: root, size: m.size}
+	if added {
+		result.size++
+	}
+
+	return result
+}
+
+/*
+Delete returns a new version of the map without the key. The receiver is returned if the key does not exist.
+
+Example
+
+	v1 := m.NewPersistentMap[string, int](nil).Set("a", 1)
+	v2 := v1.Delete("a")
+	v2.Len() // 0
+*/
+func (m *PersistentMap[K, V]) Delete(key K) *PersistentMap[K, V] {
+	root, removed := hamtRemove(m.root, nil, key, m.config.hasher(key))
+	if !removed {
+		return m
+	}
+
+	return &PersistentMap[K, V]{config: m.config, root: root, size: m.size - 1}
+}
+
+/*
+All returns an iterator over the keys and values in an unspecified but stable order.
+
+Example
+
+	for key, value := range pm.All() {
+		fmt.Printf("%s: %d", key, value)
+	}
+*/
+func (m *PersistentMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.root != nil {
+			m.root.all(yield)
+		}
+	}
+}
+
+/*
+Builder returns a PersistentBuilder starting from this version, to apply many edits without allocating a new version
+for each of them.
+
+Example
+
+	b := pm.Builder()
+	for i, name := range names {
+		b.Set(name, i)
+	}
+	pm = b.Map()
+*/
+func (m *PersistentMap[K, V]) Builder() *PersistentBuilder[K, V] {
+	return &PersistentBuilder[K, V]{config: m.config, root: m.root, size: m.size, edit: &editToken{}}
+}
+
+/*
+Equal returns true if both maps have the same keys and eq returns true for the values of every key.
+Subtrees shared between versions of the same map are not visited.
+
+Example
+
+	v1 := m.NewPersistentMap[string, int](nil).Set("a", 1)
+	v2 := v1.Set("a", 1)
+	v1.Equal(v2, func(a, b int) bool { return a == b }) // true
+*/
+func (m *PersistentMap[K, V]) Equal(other *PersistentMap[K, V], eq func(a, b V) bool) bool {
+	if m.size != other.size {
+		return false
+	}
+
+	equal := true
+	m.diff(other, eq, func(MapChange[K, V]) bool {
+		equal = false
+
+		return false
+	})
+
+	return equal
+}
+
+// MapChange is a difference between two versions of a map, as returned by PersistentMap.Diff.
+// Old is the zero value for added keys and New is the zero value for removed keys.
+type MapChange[K comparable, V any] struct {
+	Key  K
+	Old  V
+	New  V
+	Kind ChangeKind
+}
+
+/*
+Diff returns the changes from this version to other, in an unspecified order. Values present in both are compared
+with eq. Subtrees shared between versions of the same map are skipped, so diffing a version against a recent
+ancestor costs little more than the number of changes.
+
+Example
+
+	v1 := m.NewPersistentMap[string, int](nil).Set("a", 1).Set("b", 2)
+	v2 := v1.Set("a", 3).Delete("b").Set("c", 4)
+	v1.Diff(v2, func(a, b int) bool { return a == b })
+	// []m.MapChange[string, int]{
+	//	{Key: "a", Old: 1, New: 3, Kind: m.ChangeModified},
+	//	{Key: "b", Old: 2, Kind: m.ChangeRemoved},
+	//	{Key: "c", New: 4, Kind: m.ChangeAdded},
+	// } in some order
+*/
+func (m *PersistentMap[K, V]) Diff(other *PersistentMap[K, V], eq func(a, b V) bool) []MapChange[K, V] {
+	var changes []MapChange[K, V]
+	m.diff(other, eq, func(change MapChange[K, V]) bool {
+		changes = append(changes, change)
+
+		return true
+	})
+
+	return changes
+}
+
+func (m *PersistentMap[K, V]) diff(other *PersistentMap[K, V], eq func(a, b V) bool, yield func(MapChange[K, V]) bool) {
+	if m.config == other.config {
+		diffHamt(m.root, other.root, 0, eq, yield)
+
+		return
+	}
+
+	// Different hashers lay out the same keys differently, so fall back to lookups.
+	for k, v := range m.All() {
+		w, ok := other.Get(k)
+
+		switch {
+		case !ok:
+			if !yield(MapChange[K, V]{Key: k, Old: v, Kind: ChangeRemoved}) {
+				return
+			}
+		case !eq(v, w):
+			if !yield(MapChange[K, V]{Key: k, Old: v, New: w, Kind: ChangeModified}) {
+				return
+			}
+		}
+	}
+
+	for k, w := range other.All() {
+		if !m.Has(k) && !yield(MapChange[K, V]{Key: k, New: w, Kind: ChangeAdded}) {
+			return
+		}
+	}
+}
+
+// diffHamt compares two tries built with the same hasher. It returns false if yield stopped the comparison.
+func diffHamt[K comparable, V any](a, b *hamtNode[K, V], shift uint, eq func(a, b V) bool, yield func(MapChange[K, V]) bool) bool {
+	switch {
+	case a == b:
+		return true
+	case a == nil:
+		return diffLeaves(nil, b.leaves(), eq, yield)
+	case b == nil:
+		return diffLeaves(a.leaves(), nil, eq, yield)
+	case shift >= hamtMaxShift:
+		return diffLeaves(a.collisions, b.collisions, eq, yield)
+	}
+
+	for bitmap := a.bitmap | b.bitmap; bitmap != 0; bitmap &= bitmap - 1 {
+		bit := bitmap & -bitmap
+		slotA, okA := a.slot(bit)
+		slotB, okB := b.slot(bit)
+
+		var ok bool
+
+		switch {
+		case okA && okB && slotA.child != nil && slotB.child != nil:
+			ok = diffHamt(slotA.child, slotB.child, shift+hamtBits, eq, yield)
+		default:
+			ok = diffLeaves(slotA.leaves(okA), slotB.leaves(okB), eq, yield)
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (n *hamtNode[K, V]) slot(bit uint32) (hamtSlot[K, V], bool) {
+	if n.bitmap&bit == 0 {
+		return hamtSlot[K, V]{}, false
+	}
+
+	return n.slots[bits.OnesCount32(n.bitmap&(bit-1))], true
+}
+
+func (s hamtSlot[K, V]) leaves(ok bool) []hamtLeaf[K, V] {
+	switch {
+	case !ok:
+		return nil
+	case s.child == nil:
+		return []hamtLeaf[K, V]{s.leaf}
+	default:
+		return s.child.leaves()
+	}
+}
+
+// diffLeaves compares two small sets of leaves. One of them has at most one leaf unless they are hash collisions.
+func diffLeaves[K comparable, V any](a, b []hamtLeaf[K, V], eq func(a, b V) bool, yield func(MapChange[K, V]) bool) bool {
+	for _, leafA := range a {
+		i := slices.IndexFunc(b, func(leafB hamtLeaf[K, V]) bool { return leafB.key == leafA.key })
+
+		var ok bool
+
+		switch {
+		case i < 0:
+			ok = yield(MapChange[K, V]{Key: leafA.key, Old: leafA.value, Kind: ChangeRemoved})
+		case !eq(leafA.value, b[i].value):
+			ok = yield(MapChange[K, V]{Key: leafA.key, Old: leafA.value, New: b[i].value, Kind: ChangeModified})
+		default:
+			ok = true
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	for _, leafB := range b {
+		if slices.ContainsFunc(a, func(leafA hamtLeaf[K, V]) bool { return leafA.key == leafB.key }) {
+			continue
+		}
+
+		if !yield(MapChange[K, V]{Key: leafB.key, New: leafB.value, Kind: ChangeAdded}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+PersistentBuilder applies a batch of edits to a PersistentMap in place. Nodes created by the builder are modified
+directly instead of being copied; nodes shared with existing versions are copied once. A builder is not threadsafe.
+
+Example
+
+	b := m.NewPersistentMap[string, int](nil).Builder()
+	b.Set("a", 1)
+	b.Set("b", 2)
+	pm := b.Map()
+*/
+type PersistentBuilder[K comparable, V any] struct {
+	config *persistentConfig[K]
+	root   *hamtNode[K, V]
+	edit   *editToken
+	size   int
+}
+
+// Get returns the value and true if the key exists in the builder, otherwise it returns the zero value and false.
+func (b *PersistentBuilder[K, V]) Get(key K) (value V, ok bool) {
+	if b.root == nil {
+		return value, false
+	}
+
+	return b.root.get(key, b.config.hasher(key), 0)
+}
+
+// Len returns the number of entries.
+func (b *PersistentBuilder[K, V]) Len() int {
+	return b.size
+}
+
+// Set sets the value for a key.
+func (b *PersistentBuilder[K, V]) Set(key K, value V) {
+	root, added := hamtSet(b.root, b.edit, hamtLeaf[K, V]{key: key, value: value, hash: b.config.hasher(key)})
+
+	b.root = root
+	if added {
+		b.size++
+	}
+}
+
+// Delete deletes the value for a key. If the key does not exist, it does nothing.
+func (b *PersistentBuilder[K, V]) Delete(key K) {
+	root, removed := hamtRemove(b.root, b.edit, key, b.config.hasher(key))
+
+	b.root = root
+	if removed {
+		b.size--
+	}
+}
+
+// Map returns the current content of the builder as a PersistentMap. The builder remains usable; its later edits
+// do not affect the returned map.
+func (b *PersistentBuilder[K, V]) Map() *PersistentMap[K, V] {
+	b.edit = &editToken{}
+
+	return &PersistentMap[K, V]{config: b.config, root: b.root, size: b.size}
+}
+
+func hamtSet[K comparable, V any](root *hamtNode[K, V], edit *editToken, leaf hamtLeaf[K, V]) (*hamtNode[K, V], bool) {
+	if root == nil {
+		root = &hamtNode[K, V]{edit: edit}
+	}
+
+	return root.set(edit, leaf, 0)
+}
+
+func hamtRemove[K comparable, V any](root *hamtNode[K, V], edit *editToken, key K, hash uint64) (*hamtNode[K, V], bool) {
+	if root == nil {
+		return nil, false
+	}
+
+	return root.delete(edit, key, hash, 0)
+}
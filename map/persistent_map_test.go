@@ -0,0 +1,329 @@
+package m
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intEqual(a, b int) bool {
+	return a == b
+}
+
+func TestPersistentMap(t *testing.T) {
+	t.Run("Set and Delete return new versions", func(t *testing.T) {
+		v0 := NewPersistentMap[string, int](nil)
+		v1 := v0.Set("a", 1)
+		v2 := v1.Set("b", 2)
+		v3 := v2.Set("a", 3)
+		v4 := v3.Delete("b")
+
+		assert.Equal(t, map[string]int{}, Collect(v0.All()))
+		assert.Equal(t, map[string]int{"a": 1}, Collect(v1.All()))
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, Collect(v2.All()))
+		assert.Equal(t, map[string]int{"a": 3, "b": 2}, Collect(v3.All()))
+		assert.Equal(t, map[string]int{"a": 3}, Collect(v4.All()))
+		assert.Equal(t, []int{0, 1, 2, 2, 1}, []int{v0.Len(), v1.Len(), v2.Len(), v3.Len(), v4.Len()})
+
+		v, ok := v3.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 3, v)
+		assert.False(t, v4.Has("b"))
+		assert.Same(t, v4, v4.Delete("missing"))
+		assert.Same(t, v0, v0.Delete("missing"))
+		assert.Equal(t, 0, v1.Delete("a").Len())
+	})
+
+	t.Run("hash collisions", func(t *testing.T) {
+		for name, hasher := range map[string]func(string) uint64{
+			"full":    func(string) uint64 { return 42 },
+			"partial": func(s string) uint64 { return uint64(len(s)) },
+		} {
+			t.Run(name, func(t *testing.T) {
+				pm := NewPersistentMap[string, int](hasher)
+				for i := range 20 {
+					pm = pm.Set(strconv.Itoa(i), i)
+				}
+
+				assert.Equal(t, 20, pm.Len())
+
+				for i := range 20 {
+					v, ok := pm.Get(strconv.Itoa(i))
+					assert.True(t, ok)
+					assert.Equal(t, i, v)
+				}
+
+				_, ok := pm.Get("missing")
+				assert.False(t, ok)
+
+				pm = pm.Set("3", 33)
+				v, _ := pm.Get("3")
+				assert.Equal(t, 33, v)
+				assert.Equal(t, 20, pm.Len())
+
+				assert.Same(t, pm, pm.Delete("missing"))
+
+				for i := range 20 {
+					pm = pm.Delete(strconv.Itoa(i))
+				}
+
+				assert.Equal(t, 0, pm.Len())
+				assert.Empty(t, Collect(pm.All()))
+			})
+		}
+	})
+
+	t.Run("matches a builtin map", func(t *testing.T) {
+		rnd := rand.New(rand.NewPCG(1, 2))
+		expected := map[int]int{}
+		pm := NewPersistentMap[int, int](nil)
+		versions := []*PersistentMap[int, int]{pm}
+		snapshots := []map[int]int{{}}
+
+		for i := range 5000 {
+			key := rnd.IntN(500)
+			if rnd.IntN(3) == 0 {
+				delete(expected, key)
+				pm = pm.Delete(key)
+			} else {
+				expected[key] = i
+				pm = pm.Set(key, i)
+			}
+
+			if i%500 == 0 {
+				versions = append(versions, pm)
+				snapshots = append(snapshots, Collect(All(expected)))
+			}
+		}
+
+		assert.Equal(t, expected, Collect(pm.All()))
+		assert.Equal(t, len(expected), pm.Len())
+
+		for i, version := range versions {
+			assert.Equal(t, snapshots[i], Collect(version.All()), "version %d", i)
+		}
+	})
+
+	t.Run("All stops early", func(t *testing.T) {
+		pm := NewPersistentMap[int, int](func(int) uint64 { return 0 }).Set(1, 1).Set(2, 2)
+		pm = pm.Set(3, 3)
+
+		n := 0
+		for range pm.All() {
+			n++
+
+			break
+		}
+
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("versions are safe to share between goroutines", func(t *testing.T) {
+		base := NewPersistentMap[int, int](nil)
+		for i := range 100 {
+			base = base.Set(i, i)
+		}
+
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				pm := base
+				for i := range 100 {
+					pm = pm.Set(i, g)
+				}
+
+				assert.Equal(t, 100, pm.Len())
+			}()
+		}
+		wg.Wait()
+
+		for i := range 100 {
+			v, _ := base.Get(i)
+			assert.Equal(t, i, v)
+		}
+	})
+}
+
+func TestPersistentBuilder(t *testing.T) {
+	t.Run("batch edits", func(t *testing.T) {
+		base := NewPersistentMap[string, int](nil).Set("a", 1).Set("b", 2)
+
+		b := base.Builder()
+		for i := range 100 {
+			b.Set(strconv.Itoa(i), i)
+		}
+
+		b.Set("a", 10)
+		b.Delete("b")
+		b.Delete("missing")
+
+		v, ok := b.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 10, v)
+		assert.Equal(t, 101, b.Len())
+
+		pm := b.Map()
+		assert.Equal(t, 101, pm.Len())
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, Collect(base.All()))
+	})
+
+	t.Run("later edits do not affect built maps", func(t *testing.T) {
+		b := NewPersistentMap[int, int](nil).Builder()
+		for i := range 50 {
+			b.Set(i, i)
+		}
+
+		first := b.Map()
+
+		for i := range 50 {
+			b.Set(i, -i)
+		}
+
+		b.Delete(0)
+
+		second := b.Map()
+
+		for i := range 50 {
+			v, _ := first.Get(i)
+			assert.Equal(t, i, v)
+		}
+
+		assert.Equal(t, 50, first.Len())
+		assert.Equal(t, 49, second.Len())
+
+		v, _ := second.Get(1)
+		assert.Equal(t, -1, v)
+	})
+
+	t.Run("collisions", func(t *testing.T) {
+		b := NewPersistentMap[string, int](func(string) uint64 { return 7 }).Builder()
+		b.Set("a", 1)
+		b.Set("b", 2)
+		b.Set("a", 3)
+		b.Delete("b")
+
+		assert.Equal(t, map[string]int{"a": 3}, Collect(b.Map().All()))
+
+		_, ok := NewPersistentMap[string, int](nil).Builder().Get("a")
+		assert.False(t, ok)
+	})
+}
+
+func TestPersistentMapEqualAndDiff(t *testing.T) {
+	t.Run("versions of the same map", func(t *testing.T) {
+		v1 := NewPersistentMap[string, int](nil)
+		for i := range 100 {
+			v1 = v1.Set(strconv.Itoa(i), i)
+		}
+
+		v2 := v1.Set("1", 100).Delete("2").Set("new", 1)
+
+		assert.True(t, v1.Equal(v1, intEqual))
+		assert.True(t, v1.Equal(v1.Set("1", 1), intEqual))
+		assert.False(t, v1.Equal(v2, intEqual))
+		assert.False(t, v1.Equal(v1.Set("1", 2), intEqual))
+		assert.Empty(t, v1.Diff(v1, intEqual))
+
+		assert.ElementsMatch(t, []MapChange[string, int]{
+			{Key: "1", Old: 1, New: 100, Kind: ChangeModified},
+			{Key: "2", Old: 2, Kind: ChangeRemoved},
+			{Key: "new", New: 1, Kind: ChangeAdded},
+		}, v1.Diff(v2, intEqual))
+
+		assert.ElementsMatch(t, []MapChange[string, int]{
+			{Key: "1", Old: 100, New: 1, Kind: ChangeModified},
+			{Key: "2", New: 2, Kind: ChangeAdded},
+			{Key: "new", Old: 1, Kind: ChangeRemoved},
+		}, v2.Diff(v1, intEqual))
+	})
+
+	t.Run("shared subtrees are skipped", func(t *testing.T) {
+		v1 := NewPersistentMap[int, int](nil)
+		for i := range 1000 {
+			v1 = v1.Set(i, i)
+		}
+
+		v2 := v1.Set(1, -1)
+
+		calls := 0
+		changes := v1.Diff(v2, func(a, b int) bool {
+			calls++
+
+			return a == b
+		})
+
+		assert.Equal(t, []MapChange[int, int]{{Key: 1, Old: 1, New: -1, Kind: ChangeModified}}, changes)
+		assert.Less(t, calls, 50)
+	})
+
+	t.Run("empty maps", func(t *testing.T) {
+		empty := NewPersistentMap[string, int](nil)
+		full := empty.Set("a", 1)
+
+		assert.Equal(t, []MapChange[string, int]{{Key: "a", New: 1, Kind: ChangeAdded}}, empty.Diff(full, intEqual))
+		assert.Equal(t, []MapChange[string, int]{{Key: "a", Old: 1, Kind: ChangeRemoved}}, full.Diff(empty, intEqual))
+		assert.True(t, empty.Equal(full.Delete("a"), intEqual))
+	})
+
+	t.Run("collisions", func(t *testing.T) {
+		v1 := NewPersistentMap[string, int](func(string) uint64 { return 1 }).Set("a", 1).Set("b", 2)
+		v2 := v1.Set("b", 3).Set("c", 4)
+
+		assert.ElementsMatch(t, []MapChange[string, int]{
+			{Key: "b", Old: 2, New: 3, Kind: ChangeModified},
+			{Key: "c", New: 4, Kind: ChangeAdded},
+		}, v1.Diff(v2, intEqual))
+	})
+
+	t.Run("maps with different hashers", func(t *testing.T) {
+		a := NewPersistentMap[string, int](nil).Set("a", 1).Set("b", 2)
+		b := NewPersistentMap[string, int](nil).Set("b", 2).Set("a", 1)
+
+		assert.True(t, a.Equal(b, intEqual))
+		assert.False(t, a.Equal(b.Set("a", 2), intEqual))
+		assert.False(t, a.Equal(b.Delete("a").Set("c", 1), intEqual))
+
+		assert.ElementsMatch(t, []MapChange[string, int]{
+			{Key: "a", Old: 1, Kind: ChangeRemoved},
+			{Key: "b", Old: 2, New: 3, Kind: ChangeModified},
+			{Key: "c", New: 4, Kind: ChangeAdded},
+		}, a.Diff(b.Delete("a").Set("b", 3).Set("c", 4), intEqual))
+	})
+
+	t.Run("random versions", func(t *testing.T) {
+		rnd := rand.New(rand.NewPCG(3, 4))
+		v1 := NewPersistentMap[int, int](nil)
+		for i := range 300 {
+			v1 = v1.Set(rnd.IntN(400), i)
+		}
+
+		v2 := v1
+		for i := range 50 {
+			if key := rnd.IntN(400); rnd.IntN(2) == 0 {
+				v2 = v2.Delete(key)
+			} else {
+				v2 = v2.Set(key, i)
+			}
+		}
+
+		before, after := Collect(v1.All()), Collect(v2.All())
+		for _, change := range v1.Diff(v2, intEqual) {
+			switch change.Kind {
+			case ChangeAdded:
+				before[change.Key] = change.New
+			case ChangeRemoved:
+				delete(before, change.Key)
+			case ChangeModified:
+				before[change.Key] = change.New
+			}
+		}
+
+		assert.Equal(t, after, before)
+	})
+}
@@ -0,0 +1,91 @@
+package m
+
+import "sync"
+
+/*
+PoolOptions configures a Pool.
+
+Example
+
+	opts := m.PoolOptions[*bytes.Buffer]{Reset: (*bytes.Buffer).Reset}
+*/
+type PoolOptions[T any] struct {
+	// Reset is called on every value passed to Put before it goes back into the pool. Defaults to no reset.
+	Reset func(T)
+}
+
+/*
+Pool is a generic wrapper around sync.Pool. It can be used as a typed drop-in replacement for sync.Pool, e.g. to reuse
+buffers in hot paths. Like sync.Pool, values may be dropped at any time, and T should be a pointer type to avoid an
+allocation on every Put.
+
+Building with the poolstats tag makes Stats count hits and misses.
+
+Example
+
+	pool := m.NewPool(func() *bytes.Buffer { return new(bytes.Buffer) }, m.PoolOptions[*bytes.Buffer]{
+		Reset: (*bytes.Buffer).Reset,
+	})
+	buf := pool.Get()
+	defer pool.Put(buf)
+*/
+type Pool[T any] struct {
+	pool  sync.Pool
+	newFn func() T
+	reset func(T)
+	stats poolStats
+}
+
+// NewPool returns a new pointer to a Pool. newFn creates a value when the pool is empty. It panics if newFn is nil.
+func NewPool[T any](newFn func() T, opts PoolOptions[T]) *Pool[T] {
+	if newFn == nil {
+		panic("pool requires a new function")
+	}
+
+	return &Pool[T]{newFn: newFn, reset: opts.Reset}
+}
+
+/*
+Get returns a value from the pool, or a new one if the pool is empty.
+
+Example
+
+	buf := pool.Get()
+*/
+func (p *Pool[T]) Get() T {
+	if v := p.pool.Get(); v != nil {
+		p.stats.hit()
+
+		return v.(T) //nolint:errcheck
+	}
+
+	p.stats.miss()
+
+	return p.newFn()
+}
+
+/*
+Put resets the value with the Reset hook and adds it to the pool.
+
+Example
+
+	pool.Put(buf)
+*/
+func (p *Pool[T]) Put(value T) {
+	if p.reset != nil {
+		p.reset(value)
+	}
+
+	p.pool.Put(value)
+}
+
+// PoolStats holds the number of Get calls served from the pool (Hits) and by the new function (Misses).
+type PoolStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// Stats returns the hits and misses of the pool so far. It always returns zeros unless built with the poolstats tag.
+func (p *Pool[T]) Stats() PoolStats {
+	return p.stats.snapshot()
+}
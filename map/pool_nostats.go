@@ -0,0 +1,16 @@
+//go:build !poolstats
+
+package m
+
+// PoolStatsEnabled reports whether Pool counts hits and misses.
+const PoolStatsEnabled = false
+
+type poolStats struct{}
+
+func (*poolStats) hit() {}
+
+func (*poolStats) miss() {}
+
+func (*poolStats) snapshot() PoolStats {
+	return PoolStats{}
+}
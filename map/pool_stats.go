@@ -0,0 +1,25 @@
+//go:build poolstats
+
+package m
+
+import "sync/atomic"
+
+// PoolStatsEnabled reports whether Pool counts hits and misses.
+const PoolStatsEnabled = true
+
+type poolStats struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func (s *poolStats) hit() {
+	s.hits.Add(1)
+}
+
+func (s *poolStats) miss() {
+	s.misses.Add(1)
+}
+
+func (s *poolStats) snapshot() PoolStats {
+	return PoolStats{Hits: s.hits.Load(), Misses: s.misses.Load()}
+}
@@ -0,0 +1,26 @@
+//go:build poolstats
+
+package m
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolStats(t *testing.T) {
+	pool := NewPool(func() *int { return new(int) }, PoolOptions[*int]{})
+
+	first := pool.Get()
+	assert.Equal(t, PoolStats{Misses: 1}, pool.Stats())
+
+	// sync.Pool may drop values at any time, e.g. randomly under the race detector, so only the totals are exact.
+	for range 100 {
+		pool.Put(first)
+		first = pool.Get()
+	}
+
+	stats := pool.Stats()
+	assert.Equal(t, uint64(101), stats.Hits+stats.Misses)
+	assert.Positive(t, stats.Hits)
+}
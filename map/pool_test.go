@@ -0,0 +1,69 @@
+package m
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("Get creates values when empty", func(t *testing.T) {
+		created := 0
+		pool := NewPool(func() *int {
+			created++
+			v := created
+
+			return &v
+		}, PoolOptions[*int]{})
+
+		assert.Equal(t, 1, *pool.Get())
+		assert.Equal(t, 2, *pool.Get())
+	})
+
+	t.Run("Put resets values", func(t *testing.T) {
+		pool := NewPool(func() *bytes.Buffer { return new(bytes.Buffer) }, PoolOptions[*bytes.Buffer]{
+			Reset: (*bytes.Buffer).Reset,
+		})
+
+		for range 10 {
+			buf := pool.Get()
+			assert.Equal(t, 0, buf.Len())
+			buf.WriteString("dirty")
+			pool.Put(buf)
+		}
+	})
+
+	t.Run("nil new function panics", func(t *testing.T) {
+		assert.Panics(t, func() { NewPool[*int](nil, PoolOptions[*int]{}) })
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		pool := NewPool(func() *[]byte {
+			b := make([]byte, 0, 64)
+
+			return &b
+		}, PoolOptions[*[]byte]{Reset: func(b *[]byte) { *b = (*b)[:0] }})
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for range 100 {
+					b := pool.Get()
+					assert.Empty(t, *b)
+					*b = append(*b, 1, 2, 3)
+					pool.Put(b)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if !PoolStatsEnabled {
+			assert.Equal(t, PoolStats{}, pool.Stats())
+		}
+	})
+}
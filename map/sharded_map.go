@@ -0,0 +1,449 @@
+package m
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+type shard[T comparable, V any] struct {
+	items map[T]V
+	mutex sync.RWMutex
+}
+
+/*
+ShardedMap is a generic threadsafe map split into shards, each guarded by its own lock. It performs better than
+AsyncMap for write-heavy workloads with many distinct keys and exposes the same methods.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.Load("a") // 1, true
+*/
+type ShardedMap[T comparable, V any] struct {
+	hasher func(key T) uint64
+	shards []shard[T, V]
+	flight SingleFlight[T, V]
+}
+
+/*
+NewShardedMap returns a new pointer to a ShardedMap with the given number of shards. Keys are spread across the shards
+with hasher; if hasher is nil, the hasher returned by NewHasher is used.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](64, nil)
+	byID := NewShardedMap[User, int](64, func(u User) uint64 { return u.ID })
+*/
+func NewShardedMap[T comparable, V any](shards int, hasher func(key T) uint64) *ShardedMap[T, V] {
+	if shards < 1 {
+		panic("shards must be greater than or equal to 1")
+	}
+
+	if hasher == nil {
+		hasher = NewHasher[T]()
+	}
+
+	m := &ShardedMap[T, V]{hasher: hasher, shards: make([]shard[T, V], shards)}
+	for i := range m.shards {
+		m.shards[i].items = make(map[T]V)
+	}
+
+	return m
+}
+
+func (m *ShardedMap[T, V]) shard(key T) *shard[T, V] {
+	return &m.shards[m.hasher(key)%uint64(len(m.shards))]
+}
+
+/*
+Store sets the value for a key.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+*/
+func (m *ShardedMap[T, V]) Store(key T, value V) {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.items[key] = value
+}
+
+/*
+Load returns the value and true if the key exists in the map, otherwise it returns the zero value and false.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.Load("b") // 0, false
+	shardedMap.Load("a") // 1, true
+*/
+func (m *ShardedMap[T, V]) Load(key T) (value V, ok bool) {
+	s := m.shard(key)
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	value, ok = s.items[key]
+
+	return value, ok
+}
+
+/*
+LoadOrStore returns the existing value for the key if present. Otherwise, it stores and returns the given value. The loaded result is true if the value was loaded, false if stored.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.LoadOrStore("a", 1) // 1, false
+	shardedMap.LoadOrStore("a", 2) // 1, true
+*/
+func (m *ShardedMap[T, V]) LoadOrStore(key T, value V) (V, bool) {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if v, ok := s.items[key]; ok {
+		return v, true
+	}
+
+	s.items[key] = value
+
+	return value, false
+}
+
+/*
+LoadAndDelete deletes the value for a key, returning the previous value if any.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.LoadAndDelete("a") // 1, true
+	shardedMap.LoadAndDelete("a") // 0, false
+*/
+func (m *ShardedMap[T, V]) LoadAndDelete(key T) (value V, ok bool) {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	value, ok = s.items[key]
+	delete(s.items, key)
+
+	return value, ok
+}
+
+/*
+Delete deletes the value for a key. If the key does not exist, it does nothing.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.Delete("a")
+*/
+func (m *ShardedMap[T, V]) Delete(key T) {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.items, key)
+}
+
+/*
+Range calls fn sequentially for each key and value present in the map. If fn returns false, range stops the iteration.
+Each shard is copied before fn is called for its entries, so fn may modify the map.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.Range(func(key string, value int) bool {
+		fmt.Printf("%s: %d", key, value)
+		return true
+	})
+*/
+func (m *ShardedMap[T, V]) Range(fn func(key T, value V) bool) {
+	for i := range m.shards {
+		for _, entry := range m.shards[i].entries() {
+			if !fn(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+/*
+All returns an iterator over the keys and values present in the map. Each shard is copied before its entries are
+yielded, so the loop body may modify the map.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	for key, value := range shardedMap.All() {
+		fmt.Printf("%s: %d", key, value)
+	}
+*/
+func (m *ShardedMap[T, V]) All() iter.Seq2[T, V] {
+	return m.Range
+}
+
+/*
+Swap swaps the value for a key and returns the previous value if any. The loaded result reports whether the key was present.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Swap("a", 1) // 0, false
+	shardedMap.Swap("a", 2) // 1, true
+*/
+func (m *ShardedMap[T, V]) Swap(key T, value V) (previous V, loaded bool) {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous, loaded = s.items[key]
+	s.items[key] = value
+
+	return previous, loaded
+}
+
+/*
+Clear deletes all the entries.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.Clear()
+	shardedMap.Len() // 0
+*/
+func (m *ShardedMap[T, V]) Clear() {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mutex.Lock()
+		clear(s.items)
+		s.mutex.Unlock()
+	}
+}
+
+/*
+Len returns the number of entries. Shards are counted one after another, so the result is only a snapshot under concurrent writes.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.Len() // 1
+*/
+func (m *ShardedMap[T, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mutex.RLock()
+		n += len(s.items)
+		s.mutex.RUnlock()
+	}
+
+	return n
+}
+
+/*
+Keys returns a snapshot of the keys in the map.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.Keys() // []string{"a"}
+*/
+func (m *ShardedMap[T, V]) Keys() []T {
+	var result []T
+	m.Range(func(key T, _ V) bool {
+		result = append(result, key)
+
+		return true
+	})
+
+	return result
+}
+
+/*
+Values returns a snapshot of the values in the map.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.Values() // []int{1}
+*/
+func (m *ShardedMap[T, V]) Values() []V {
+	var result []V
+	m.Range(func(_ T, value V) bool {
+		result = append(result, value)
+
+		return true
+	})
+
+	return result
+}
+
+/*
+Compute atomically replaces the value for a key with the result of fn. fn receives the current value and whether
+the key was present, and returns the new value and whether to keep it; returning false deletes the key.
+Compute returns the new value and whether the key is present afterwards. fn runs with the shard locked, so it must
+not access the map.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Compute("a", func(value int, loaded bool) (int, bool) {
+		return value + 1, true
+	}) // 1, true
+*/
+func (m *ShardedMap[T, V]) Compute(key T, fn func(value V, loaded bool) (V, bool)) (value V, ok bool) {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current, loaded := s.items[key]
+
+	value, ok = fn(current, loaded)
+	if !ok {
+		delete(s.items, key)
+
+		return value, ok
+	}
+
+	s.items[key] = value
+
+	return value, ok
+}
+
+/*
+ComputeIfAbsent stores the result of fn if the key is not present and returns the value stored in the map.
+fn returns the value and whether to store it. The loaded result is true if the value was already present.
+fn runs with the shard locked, so it must not access the map.
+
+Example
+
+	shardedMap := NewShardedMap[string, []int](32, nil)
+	shardedMap.ComputeIfAbsent("a", func() ([]int, bool) { return []int{1}, true }) // []int{1}, false
+*/
+func (m *ShardedMap[T, V]) ComputeIfAbsent(key T, fn func() (V, bool)) (value V, loaded bool) {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if v, ok := s.items[key]; ok {
+		return v, true
+	}
+
+	value, ok := fn()
+	if !ok {
+		var zero V
+
+		return zero, false
+	}
+
+	s.items[key] = value
+
+	return value, false
+}
+
+/*
+ComputeIfPresent atomically replaces the value for a key with the result of fn if the key is present.
+fn returns the new value and whether to keep it; returning false deletes the key.
+ComputeIfPresent returns the new value and whether the key is present afterwards.
+fn runs with the shard locked, so it must not access the map.
+
+Example
+
+	shardedMap := NewShardedMap[string, int](32, nil)
+	shardedMap.Store("a", 1)
+	shardedMap.ComputeIfPresent("a", func(value int) (int, bool) { return value * 2, true }) // 2, true
+*/
+func (m *ShardedMap[T, V]) ComputeIfPresent(key T, fn func(value V) (V, bool)) (value V, ok bool) {
+	return m.Compute(key, func(value V, loaded bool) (V, bool) {
+		if !loaded {
+			return value, false
+		}
+
+		return fn(value)
+	})
+}
+
+/*
+LoadOrCompute returns the existing value for the key if present. Otherwise, it stores and returns the result of fn.
+Concurrent callers for the same key wait for a single call of fn, which runs without holding the shard lock.
+The loaded result is true if the value was not computed by this call. If fn panics, the panic is re-raised in every
+waiting caller as a *PanicError.
+
+Example
+
+	shardedMap := NewShardedMap[string, *Client](32, nil)
+	client, loaded := shardedMap.LoadOrCompute("eu", func() *Client {
+		return dial("eu")
+	})
+*/
+func (m *ShardedMap[T, V]) LoadOrCompute(key T, fn func() V) (value V, loaded bool) {
+	if v, ok := m.Load(key); ok {
+		return v, true
+	}
+
+	computed := false
+	value, _, _ = m.flight.Do(context.Background(), key, func(context.Context) (V, error) {
+		if v, ok := m.Load(key); ok {
+			return v, nil
+		}
+
+		v, loaded := m.LoadOrStore(key, fn())
+		computed = !loaded
+
+		return v, nil
+	})
+
+	return value, !computed
+}
+
+func (m *ShardedMap[T, V]) compareAndSwap(key T, old, value V, equal func(a, b V) bool) bool {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	v, ok := s.items[key]
+	if !ok || !equal(v, old) {
+		return false
+	}
+
+	s.items[key] = value
+
+	return true
+}
+
+func (m *ShardedMap[T, V]) compareAndDelete(key T, old V, equal func(a, b V) bool) bool {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	v, ok := s.items[key]
+	if !ok || !equal(v, old) {
+		return false
+	}
+
+	delete(s.items, key)
+
+	return true
+}
+
+func (s *shard[T, V]) entries() []Entry[T, V] {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return Entries(s.items)
+}
@@ -0,0 +1,251 @@
+package m
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedMap(t *testing.T) {
+	t.Run("Store/Load", func(t *testing.T) {
+		m := NewShardedMap[string, int](4, nil)
+		m.Store("a", 1)
+		v, ok := m.Load("a")
+
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+
+		v, ok = m.Load("b")
+
+		assert.False(t, ok)
+		assert.Equal(t, 0, v)
+	})
+
+	t.Run("LoadOrStore", func(t *testing.T) {
+		m := NewShardedMap[string, int](4, nil)
+		v, ok := m.LoadOrStore("a", 1)
+
+		assert.False(t, ok)
+		assert.Equal(t, 1, v)
+
+		v, ok = m.LoadOrStore("a", 2)
+
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("LoadAndDelete/Delete", func(t *testing.T) {
+		m := NewShardedMap[string, int](4, nil)
+		m.Store("a", 1)
+		v, ok := m.LoadAndDelete("a")
+
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+
+		_, ok = m.LoadAndDelete("a")
+		assert.False(t, ok)
+
+		m.Store("b", 2)
+		m.Delete("b")
+		_, ok = m.Load("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		m := NewShardedMap[int, int](4, nil)
+		for i := 0; i < 10; i++ {
+			m.Store(i, i*10)
+		}
+
+		seen := map[int]int{}
+		m.Range(func(key, value int) bool {
+			seen[key] = value
+			m.Delete(key)
+
+			return true
+		})
+		assert.Len(t, seen, 10)
+		assert.Equal(t, 90, seen[9])
+		assert.Equal(t, 0, m.Len())
+
+		m.Store(1, 1)
+		m.Store(2, 2)
+		runs := 0
+		m.Range(func(int, int) bool {
+			runs++
+
+			return false
+		})
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("Swap/Clear/Len/Keys/Values", func(t *testing.T) {
+		m := NewShardedMap[string, int](4, nil)
+		_, loaded := m.Swap("a", 1)
+		assert.False(t, loaded)
+
+		v, loaded := m.Swap("a", 2)
+		assert.True(t, loaded)
+		assert.Equal(t, 1, v)
+
+		m.Store("b", 3)
+		assert.Equal(t, 2, m.Len())
+		assert.ElementsMatch(t, []string{"a", "b"}, m.Keys())
+		assert.ElementsMatch(t, []int{2, 3}, m.Values())
+
+		m.Clear()
+		assert.Equal(t, 0, m.Len())
+	})
+
+	t.Run("CompareAndSwap/CompareAndDelete", func(t *testing.T) {
+		m := NewShardedMap[string, int64](4, nil)
+		m.Store("a", 1)
+
+		assert.False(t, CompareAndSwap(m, "a", 2, 3))
+		assert.True(t, CompareAndSwap(m, "a", 1, 3))
+		assert.False(t, CompareAndDelete(m, "a", 1))
+		assert.True(t, CompareAndDelete(m, "a", 3))
+		assert.Equal(t, 0, m.Len())
+	})
+
+	t.Run("Compute", func(t *testing.T) {
+		m := NewShardedMap[string, int](4, nil)
+		var wg sync.WaitGroup
+
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.Compute("a", func(value int, _ bool) (int, bool) {
+					return value + 1, true
+				})
+			}()
+		}
+		wg.Wait()
+
+		v, _ := m.Load("a")
+		assert.Equal(t, 100, v)
+
+		_, ok := m.Compute("a", func(int, bool) (int, bool) { return 0, false })
+		assert.False(t, ok)
+		assert.Equal(t, 0, m.Len())
+	})
+
+	t.Run("ComputeIfAbsent/ComputeIfPresent", func(t *testing.T) {
+		m := NewShardedMap[string, int](4, nil)
+		double := func(value int) (int, bool) { return value * 2, true }
+
+		_, ok := m.ComputeIfPresent("a", double)
+		assert.False(t, ok)
+
+		v, loaded := m.ComputeIfAbsent("a", func() (int, bool) { return 1, true })
+		assert.False(t, loaded)
+		assert.Equal(t, 1, v)
+
+		v, loaded = m.ComputeIfAbsent("a", func() (int, bool) { return 5, true })
+		assert.True(t, loaded)
+		assert.Equal(t, 1, v)
+
+		_, loaded = m.ComputeIfAbsent("b", func() (int, bool) { return 5, false })
+		assert.False(t, loaded)
+		assert.Equal(t, 1, m.Len())
+
+		v, ok = m.ComputeIfPresent("a", double)
+		assert.True(t, ok)
+		assert.Equal(t, 2, v)
+	})
+
+	t.Run("LoadOrCompute", func(t *testing.T) {
+		m := NewShardedMap[string, int](4, nil)
+		var calls atomic.Int32
+		var wg sync.WaitGroup
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, _ := m.LoadOrCompute("a", func() int {
+					calls.Add(1)
+					time.Sleep(10 * time.Millisecond)
+
+					return 42
+				})
+				assert.Equal(t, 42, v)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("custom hasher", func(t *testing.T) {
+		m := NewShardedMap[int, int](8, func(key int) uint64 { return uint64(key) })
+		for i := 0; i < 16; i++ {
+			m.Store(i, i)
+		}
+
+		for i := range m.shards {
+			assert.Len(t, m.shards[i].items, 2)
+		}
+	})
+
+	t.Run("panics on invalid shard count", func(t *testing.T) {
+		assert.Panics(t, func() { NewShardedMap[string, int](0, nil) })
+	})
+}
+
+type concurrentMap interface {
+	Load(key string) (int, bool)
+	Store(key string, value int)
+}
+
+func benchmarkMap(b *testing.B, m concurrentMap, writePercent int) {
+	b.Helper()
+
+	keys := make([]string, 1<<14)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		m.Store(keys[i], i)
+	}
+
+	var seq atomic.Uint64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(seq.Add(1) * 7919)
+		for pb.Next() {
+			i++
+			key := keys[i%len(keys)]
+
+			if i%100 < writePercent {
+				m.Store(key, i)
+			} else {
+				m.Load(key)
+			}
+		}
+	})
+}
+
+func BenchmarkConcurrentMaps(b *testing.B) {
+	mixes := []struct {
+		name         string
+		writePercent int
+	}{
+		{"read-heavy", 10},
+		{"write-heavy", 90},
+	}
+
+	for _, mix := range mixes {
+		b.Run("AsyncMap/"+mix.name, func(b *testing.B) {
+			benchmarkMap(b, NewAsyncMap[string, int](), mix.writePercent)
+		})
+
+		b.Run("ShardedMap/"+mix.name, func(b *testing.B) {
+			benchmarkMap(b, NewShardedMap[string, int](64, nil), mix.writePercent)
+		})
+	}
+}
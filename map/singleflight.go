@@ -0,0 +1,170 @@
+package m
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+/*
+PanicError is the value re-panicked in every waiter of a SingleFlight call whose function panicked.
+
+Example
+
+	defer func() {
+		if r := recover(); r != nil {
+			perr := r.(*m.PanicError)
+			fmt.Println(perr.Value, string(perr.Stack))
+		}
+	}()
+*/
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("%v\n\n%s", p.Value, p.Stack)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (p *PanicError) Unwrap() error {
+	err, ok := p.Value.(error)
+	if !ok {
+		return nil
+	}
+
+	return err
+}
+
+type call[V any] struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	val      V
+	err      error
+	panicErr *PanicError
+	waiters  int
+	dups     int
+}
+
+/*
+SingleFlight collapses concurrent calls for the same key into a single execution. The zero value is ready to use.
+
+Example
+
+	var flight m.SingleFlight[string, User]
+	user, shared, err := flight.Do(ctx, "user-1", func(ctx context.Context) (User, error) {
+		return fetchUser(ctx, "user-1")
+	})
+	// concurrent callers for "user-1" share a single fetchUser call
+*/
+type SingleFlight[K comparable, V any] struct {
+	calls map[K]*call[V]
+	mutex sync.Mutex
+}
+
+// NewSingleFlight returns a new pointer to a SingleFlight.
+func NewSingleFlight[K comparable, V any]() *SingleFlight[K, V] {
+	return &SingleFlight[K, V]{}
+}
+
+/*
+Do executes fn for the key, making sure only one execution is in flight for a given key at a time.
+Callers arriving while an execution is in flight wait for it and receive the same results. The shared
+result is true if the value was handed to more than one caller.
+
+fn receives a context that is detached from the callers' contexts and is canceled once every waiter
+has given up. A waiter whose own ctx is done returns ctx.Err() immediately. If fn panics, the panic is
+re-raised in every waiter as a *PanicError.
+
+Example
+
+	var flight m.SingleFlight[string, int]
+	v, shared, err := flight.Do(ctx, "a", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	// v == 1, shared == false, err == nil
+*/
+func (g *SingleFlight[K, V]) Do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (v V, shared bool, err error) {
+	g.mutex.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+
+	c, ok := g.calls[key]
+	if ok {
+		c.dups++
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &call[V]{cancel: cancel, done: make(chan struct{})}
+		g.calls[key] = c
+
+		go g.run(callCtx, key, c, fn)
+	}
+	c.waiters++
+	g.mutex.Unlock()
+
+	select {
+	case <-c.done:
+		if c.panicErr != nil {
+			panic(c.panicErr)
+		}
+
+		return c.val, c.dups > 0, c.err
+	case <-ctx.Done():
+		g.leave(key, c)
+
+		return v, ok, ctx.Err()
+	}
+}
+
+/*
+Forget tells the SingleFlight to forget about a key. Future calls to Do for this key will execute the function
+rather than waiting for an earlier call to complete. Callers already waiting still receive its results.
+
+Example
+
+	var flight m.SingleFlight[string, int]
+	flight.Forget("a")
+*/
+func (g *SingleFlight[K, V]) Forget(key K) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	delete(g.calls, key)
+}
+
+func (g *SingleFlight[K, V]) run(ctx context.Context, key K, c *call[V], fn func(ctx context.Context) (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.panicErr = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+
+		g.mutex.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mutex.Unlock()
+
+		c.cancel()
+		close(c.done)
+	}()
+
+	c.val, c.err = fn(ctx)
+}
+
+func (g *SingleFlight[K, V]) leave(key K, c *call[V]) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	c.waiters--
+	if c.waiters > 0 {
+		return
+	}
+
+	c.cancel()
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
@@ -0,0 +1,208 @@
+package m
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleFlight(t *testing.T) {
+	t.Run("Do", func(t *testing.T) {
+		g := NewSingleFlight[string, int]()
+		v, shared, err := g.Do(context.Background(), "a", func(context.Context) (int, error) {
+			return 1, nil
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, shared)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("Do returns the error", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		errBoom := errors.New("boom")
+		_, _, err := g.Do(context.Background(), "a", func(context.Context) (int, error) {
+			return 0, errBoom
+		})
+
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("deduplicates concurrent calls", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		var calls atomic.Int32
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		fn := func(context.Context) (int, error) {
+			calls.Add(1)
+			close(started)
+			<-release
+
+			return 42, nil
+		}
+
+		var wg sync.WaitGroup
+		results := make([]int, 5)
+		shared := make([]bool, 5)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[0], shared[0], _ = g.Do(context.Background(), "a", fn)
+		}()
+		<-started
+
+		for i := 1; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], shared[i], _ = g.Do(context.Background(), "a", fn)
+			}()
+		}
+
+		waitForWaiters(t, &g, "a", 5)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, []int{42, 42, 42, 42, 42}, results)
+		assert.Equal(t, []bool{true, true, true, true, true}, shared)
+	})
+
+	t.Run("Forget", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		go func() {
+			_, _, _ = g.Do(context.Background(), "a", func(context.Context) (int, error) {
+				close(started)
+				<-release
+
+				return 1, nil
+			})
+		}()
+		<-started
+
+		g.Forget("a")
+		v, shared, err := g.Do(context.Background(), "a", func(context.Context) (int, error) {
+			return 2, nil
+		})
+		close(release)
+
+		assert.NoError(t, err)
+		assert.False(t, shared)
+		assert.Equal(t, 2, v)
+	})
+
+	t.Run("propagates panics to all waiters", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		fn := func(context.Context) (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		}
+
+		var wg sync.WaitGroup
+		panics := make([]any, 3)
+		do := func(i int) {
+			defer wg.Done()
+			defer func() { panics[i] = recover() }()
+			_, _, _ = g.Do(context.Background(), "a", fn)
+		}
+
+		wg.Add(1)
+		go do(0)
+		<-started
+
+		wg.Add(2)
+		go do(1)
+		go do(2)
+
+		waitForWaiters(t, &g, "a", 3)
+		close(release)
+		wg.Wait()
+
+		for _, p := range panics {
+			perr, ok := p.(*PanicError)
+			assert.True(t, ok)
+			assert.Equal(t, "boom", perr.Value)
+		}
+	})
+
+	t.Run("waiter context cancellation", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		go func() {
+			_, _, _ = g.Do(context.Background(), "a", func(context.Context) (int, error) {
+				close(started)
+				<-release
+
+				return 1, nil
+			})
+		}()
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, shared, err := g.Do(ctx, "a", func(context.Context) (int, error) {
+			return 2, nil
+		})
+		close(release)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.True(t, shared)
+	})
+
+	t.Run("cancels the call once all waiters gave up", func(t *testing.T) {
+		var g SingleFlight[string, int]
+		ctx, cancel := context.WithCancel(context.Background())
+		canceled := make(chan error)
+		started := make(chan struct{})
+
+		go func() {
+			<-started
+			cancel()
+		}()
+
+		_, _, err := g.Do(ctx, "a", func(ctx context.Context) (int, error) {
+			close(started)
+			<-ctx.Done()
+			canceled <- ctx.Err()
+
+			return 0, ctx.Err()
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		select {
+		case err := <-canceled:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("expected the call context to be canceled")
+		}
+	})
+}
+
+func waitForWaiters[K comparable, V any](t *testing.T, g *SingleFlight[K, V], key K, n int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		g.mutex.Lock()
+		defer g.mutex.Unlock()
+
+		c, ok := g.calls[key]
+
+		return ok && c.waiters == n
+	}, time.Second, time.Millisecond)
+}
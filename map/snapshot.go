@@ -0,0 +1,121 @@
+package m
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Codec encodes and decodes the entries of a map snapshot.
+type Codec interface {
+	Encode(w io.Writer, v any) error
+	Decode(r io.Reader, v any) error
+}
+
+// GobCodec is a Codec using encoding/gob. Interface values must be registered with gob.Register.
+type GobCodec struct{}
+
+// Encode writes v to w with gob.
+func (GobCodec) Encode(w io.Writer, v any) error {
+	return gob.NewEncoder(w).Encode(v) //nolint:wrapcheck
+}
+
+// Decode reads v from r with gob.
+func (GobCodec) Decode(r io.Reader, v any) error {
+	return gob.NewDecoder(r).Decode(v) //nolint:wrapcheck
+}
+
+// JSONCodec is a Codec using encoding/json.
+type JSONCodec struct{}
+
+// Encode writes v to w as JSON.
+func (JSONCodec) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v) //nolint:wrapcheck
+}
+
+// Decode reads v from r as JSON.
+func (JSONCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v) //nolint:wrapcheck
+}
+
+/*
+Snapshot writes the entries of the map to w with codec. If codec is nil, GobCodec is used.
+The snapshot is consistent if the map was created with AsyncMapOptions.ConsistentSnapshots: writers are then blocked
+only while the entries are copied, not while they are encoded. Otherwise, like sync.Map.Range, it may reflect only some
+of the writes made while it is taken.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	asyncMap.Store("a", 1)
+	f, _ := os.Create("state.gob")
+	defer f.Close()
+	err := asyncMap.Snapshot(f, m.GobCodec{})
+*/
+func (m *AsyncMap[T, V]) Snapshot(w io.Writer, codec Codec) error {
+	if codec == nil {
+		codec = GobCodec{}
+	}
+
+	m.snapshot.Lock()
+	entries := CollectEntries(m.Range)
+	m.snapshot.Unlock()
+
+	if entries == nil {
+		entries = []Entry[T, V]{}
+	}
+
+	if err := codec.Encode(w, entries); err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+
+	return nil
+}
+
+/*
+Restore replaces the entries of the map with the ones of a snapshot read from r with codec. If codec is nil,
+GobCodec is used. The snapshot is decoded before the map is touched, so the map is unchanged if decoding fails.
+Watchers receive a delete event for every previous entry and a put event for every restored one.
+
+Restore is not atomic for readers: Load, Range and All may see the map empty or partly restored while it runs.
+Writes are blocked while it runs if the map was created with AsyncMapOptions.ConsistentSnapshots or has watchers;
+otherwise they may be kept or lost.
+
+Example
+
+	asyncMap := NewAsyncMap[string, int]()
+	f, _ := os.Open("state.gob")
+	defer f.Close()
+	err := asyncMap.Restore(f, m.GobCodec{})
+*/
+func (m *AsyncMap[T, V]) Restore(r io.Reader, codec Codec) error {
+	if codec == nil {
+		codec = GobCodec{}
+	}
+
+	var entries []Entry[T, V]
+	if err := codec.Decode(r, &entries); err != nil {
+		return fmt.Errorf("decoding snapshot: %w", err)
+	}
+
+	m.snapshot.Lock()
+	defer m.snapshot.Unlock()
+
+	m.hub.mutex.Lock()
+	defer m.hub.mutex.Unlock()
+
+	m.item.Range(func(key, value any) bool {
+		m.item.Delete(key)
+		m.publishDelete(key.(T), value) //nolint:errcheck
+
+		return true
+	})
+
+	for _, entry := range entries {
+		m.item.Store(entry.Key, &entry.Value)
+		m.publishPut(entry.Key, nil, false, entry.Value)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package m
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestSnapshot(t *testing.T) {
+	type session struct {
+		User  string
+		Roles []string
+	}
+
+	for name, codec := range map[string]Codec{"gob": GobCodec{}, "json": JSONCodec{}, "default": nil} {
+		t.Run(name+" round trip", func(t *testing.T) {
+			source := NewAsyncMap[string, session]()
+			source.Store("a", session{User: "ann", Roles: []string{"admin"}})
+			source.Store("b", session{User: "bob"})
+
+			var buf bytes.Buffer
+			assert.NoError(t, source.Snapshot(&buf, codec))
+
+			target := NewAsyncMap[string, session]()
+			target.Store("stale", session{User: "old"})
+			assert.NoError(t, target.Restore(&buf, codec))
+
+			assert.Equal(t, Collect(source.All()), Collect(target.All()))
+		})
+	}
+
+	t.Run("empty map", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, NewAsyncMap[int, int]().Snapshot(&buf, JSONCodec{}))
+		assert.Equal(t, "[]\n", buf.String())
+
+		target := NewAsyncMap[int, int]()
+		target.Store(1, 1)
+		assert.NoError(t, target.Restore(&buf, JSONCodec{}))
+		assert.Equal(t, 0, target.Len())
+	})
+
+	t.Run("encoding error", func(t *testing.T) {
+		asyncMap := NewAsyncMap[string, int]()
+		asyncMap.Store("a", 1)
+
+		err := asyncMap.Snapshot(failingWriter{}, JSONCodec{})
+		assert.ErrorContains(t, err, "encoding snapshot: disk full")
+	})
+
+	t.Run("decoding error leaves the map unchanged", func(t *testing.T) {
+		asyncMap := NewAsyncMap[string, int]()
+		asyncMap.Store("a", 1)
+
+		err := asyncMap.Restore(strings.NewReader("not json"), JSONCodec{})
+		assert.ErrorContains(t, err, "decoding snapshot")
+		assert.Equal(t, map[string]int{"a": 1}, Collect(asyncMap.All()))
+	})
+
+	t.Run("consistent under concurrent writes", func(t *testing.T) {
+		// The writer stores keys in increasing order, so every consistent snapshot holds the keys 0 to n-1.
+		asyncMap := NewAsyncMapWithOptions[int, int](AsyncMapOptions{ConsistentSnapshots: true})
+		stop := make(chan struct{})
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+					asyncMap.Store(i, i)
+				}
+			}
+		}()
+
+		for range 50 {
+			var buf bytes.Buffer
+			assert.NoError(t, asyncMap.Snapshot(&buf, GobCodec{}))
+
+			restored := NewAsyncMap[int, int]()
+			assert.NoError(t, restored.Restore(&buf, GobCodec{}))
+
+			snapshot := Collect(restored.All())
+			for i := range len(snapshot) {
+				assert.Contains(t, snapshot, i)
+			}
+		}
+
+		close(stop)
+		<-done
+	})
+}
@@ -0,0 +1,438 @@
+package m
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+// skipListMaxLevel bounds the height of the skip list. With a promotion probability of 1/4 it supports 4^24 entries
+// before lookups start to degrade.
+const skipListMaxLevel = 24
+
+type skipNode[K, V any] struct {
+	key   K
+	value V
+	next  []*skipNode[K, V]
+}
+
+/*
+SortedMap is a map that keeps its keys sorted, backed by a skip list. Lookups, inserts and deletes take O(log n) on
+average, and it supports ordered traversal, range scans and floor and ceiling lookups. It is not safe for concurrent
+use; use SyncSortedMap instead.
+
+Example
+
+	sm := m.NewSortedMap[int, string]()
+	sm.Set(20, "b")
+	sm.Set(10, "a")
+	sm.Keys()    // []int{10, 20}
+	sm.Floor(15) // 10, "a", true
+*/
+type SortedMap[K, V any] struct {
+	head  *skipNode[K, V]
+	cmp   func(a, b K) int
+	level int
+	size  int
+}
+
+// NewSortedMap returns a new pointer to a SortedMap whose keys are sorted in their natural order.
+func NewSortedMap[K constraints.Ordered, V any]() *SortedMap[K, V] {
+	return NewSortedMapFunc[K, V](cmp.Compare[K])
+}
+
+/*
+NewSortedMapFunc returns a new pointer to a SortedMap whose keys are sorted by cmp.
+cmp returns a negative number when a < b, a positive number when a > b and zero when the keys are equal.
+
+Example
+
+	sm := m.NewSortedMapFunc[time.Time, float64](func(a, b time.Time) int { return a.Compare(b) })
+*/
+func NewSortedMapFunc[K, V any](cmp func(a, b K) int) *SortedMap[K, V] {
+	return &SortedMap[K, V]{
+		head:  &skipNode[K, V]{next: make([]*skipNode[K, V], skipListMaxLevel)},
+		cmp:   cmp,
+		level: 1,
+	}
+}
+
+// predecessors fills update with the last node before key on every level and returns the first node at or after key.
+func (sm *SortedMap[K, V]) predecessors(key K, update []*skipNode[K, V]) *skipNode[K, V] {
+	node := sm.head
+	for level := sm.level - 1; level >= 0; level-- {
+		for node.next[level] != nil && sm.cmp(node.next[level].key, key) < 0 {
+			node = node.next[level]
+		}
+
+		if update != nil {
+			update[level] = node
+		}
+	}
+
+	return node.next[0]
+}
+
+func (sm *SortedMap[K, V]) find(key K) *skipNode[K, V] {
+	node := sm.predecessors(key, nil)
+	if node != nil && sm.cmp(node.key, key) == 0 {
+		return node
+	}
+
+	return nil
+}
+
+/*
+Set sets the value for a key.
+
+Example
+
+	sm := m.NewSortedMap[int, string]()
+	sm.Set(1, "a")
+*/
+func (sm *SortedMap[K, V]) Set(key K, value V) {
+	var update [skipListMaxLevel]*skipNode[K, V]
+
+	node := sm.predecessors(key, update[:])
+	if node != nil && sm.cmp(node.key, key) == 0 {
+		node.value = value
+
+		return
+	}
+
+	level := randomLevel()
+	for ; sm.level < level; sm.level++ {
+		update[sm.level] = sm.head
+	}
+
+	node = &skipNode[K, V]{key: key, value: value, next: make([]*skipNode[K, V], level)}
+	for i := range level {
+		node.next[i] = update[i].next[i]
+		update[i].next[i] = node
+	}
+
+	sm.size++
+}
+
+// randomLevel returns a level with probability 1/4 of going one level higher.
+func randomLevel() int {
+	return min(bits.TrailingZeros64(rand.Uint64())/2+1, skipListMaxLevel) //nolint:gosec
+}
+
+/*
+Get returns the value and true if the key exists in the map, otherwise it returns the zero value and false.
+
+Example
+
+	sm.Get(1) // "a", true
+*/
+func (sm *SortedMap[K, V]) Get(key K) (value V, ok bool) {
+	if node := sm.find(key); node != nil {
+		return node.value, true
+	}
+
+	return value, false
+}
+
+// Has returns true if the key exists in the map.
+func (sm *SortedMap[K, V]) Has(key K) bool {
+	return sm.find(key) != nil
+}
+
+/*
+Delete deletes the value for a key. If the key does not exist, it does nothing.
+
+Example
+
+	sm.Delete(1)
+*/
+func (sm *SortedMap[K, V]) Delete(key K) {
+	var update [skipListMaxLevel]*skipNode[K, V]
+
+	node := sm.predecessors(key, update[:])
+	if node == nil || sm.cmp(node.key, key) != 0 {
+		return
+	}
+
+	for i := range node.next {
+		update[i].next[i] = node.next[i]
+	}
+
+	for sm.level > 1 && sm.head.next[sm.level-1] == nil {
+		sm.level--
+	}
+
+	sm.size--
+}
+
+// Len returns the number of entries.
+func (sm *SortedMap[K, V]) Len() int {
+	return sm.size
+}
+
+/*
+First returns the smallest key and its value. ok is false if the map is empty.
+
+Example
+
+	sm.First() // 10, "a", true
+*/
+func (sm *SortedMap[K, V]) First() (key K, value V, ok bool) {
+	return sm.entry(sm.head.next[0])
+}
+
+/*
+Last returns the largest key and its value. ok is false if the map is empty.
+
+Example
+
+	sm.Last() // 20, "b", true
+*/
+func (sm *SortedMap[K, V]) Last() (key K, value V, ok bool) {
+	node := sm.head
+	for level := sm.level - 1; level >= 0; level-- {
+		for node.next[level] != nil {
+			node = node.next[level]
+		}
+	}
+
+	if node == sm.head {
+		return key, value, false
+	}
+
+	return node.key, node.value, true
+}
+
+/*
+Floor returns the largest key less than or equal to the given key and its value. ok is false if there is none.
+
+Example
+
+	sm.Floor(15) // 10, "a", true
+	sm.Floor(5)  // 0, "", false
+*/
+func (sm *SortedMap[K, V]) Floor(key K) (k K, value V, ok bool) {
+	var update [skipListMaxLevel]*skipNode[K, V]
+
+	node := sm.predecessors(key, update[:])
+	if node != nil && sm.cmp(node.key, key) == 0 {
+		return node.key, node.value, true
+	}
+
+	if update[0] == sm.head {
+		return k, value, false
+	}
+
+	return update[0].key, update[0].value, true
+}
+
+/*
+Ceiling returns the smallest key greater than or equal to the given key and its value. ok is false if there is none.
+
+Example
+
+	sm.Ceiling(15) // 20, "b", true
+	sm.Ceiling(25) // 0, "", false
+*/
+func (sm *SortedMap[K, V]) Ceiling(key K) (k K, value V, ok bool) {
+	return sm.entry(sm.predecessors(key, nil))
+}
+
+/*
+Range returns an iterator over the keys in [from, to) and their values, in ascending order.
+
+Example
+
+	for t, v := range series.Range(start, end) {
+		fmt.Println(t, v)
+	}
+*/
+func (sm *SortedMap[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := sm.predecessors(from, nil); node != nil && sm.cmp(node.key, to) < 0; node = node.next[0] {
+			if !yield(node.key, node.value) {
+				return
+			}
+		}
+	}
+}
+
+/*
+All returns an iterator over the keys and values in ascending order.
+
+Example
+
+	for key, value := range sm.All() {
+		fmt.Println(key, value)
+	}
+*/
+func (sm *SortedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := sm.head.next[0]; node != nil; node = node.next[0] {
+			if !yield(node.key, node.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns the keys in ascending order.
+func (sm *SortedMap[K, V]) Keys() []K {
+	result := make([]K, 0, sm.size)
+	for k := range sm.All() {
+		result = append(result, k)
+	}
+
+	return result
+}
+
+// Values returns the values in the order of their keys.
+func (sm *SortedMap[K, V]) Values() []V {
+	result := make([]V, 0, sm.size)
+	for _, v := range sm.All() {
+		result = append(result, v)
+	}
+
+	return result
+}
+
+func (sm *SortedMap[K, V]) entry(node *skipNode[K, V]) (key K, value V, ok bool) {
+	if node == nil {
+		return key, value, false
+	}
+
+	return node.key, node.value, true
+}
+
+/*
+SyncSortedMap is a threadsafe SortedMap. Iterators work on a snapshot taken when the iteration starts, so the loop
+body may modify the map.
+
+Example
+
+	series := m.NewSyncSortedMap[int64, float64]()
+	series.Set(time.Now().Unix(), 0.5)
+*/
+type SyncSortedMap[K, V any] struct {
+	items *SortedMap[K, V]
+	mutex sync.RWMutex
+}
+
+// NewSyncSortedMap returns a new pointer to a SyncSortedMap whose keys are sorted in their natural order.
+func NewSyncSortedMap[K constraints.Ordered, V any]() *SyncSortedMap[K, V] {
+	return &SyncSortedMap[K, V]{items: NewSortedMap[K, V]()}
+}
+
+// NewSyncSortedMapFunc returns a new pointer to a SyncSortedMap whose keys are sorted by cmp.
+func NewSyncSortedMapFunc[K, V any](cmp func(a, b K) int) *SyncSortedMap[K, V] {
+	return &SyncSortedMap[K, V]{items: NewSortedMapFunc[K, V](cmp)}
+}
+
+// Set sets the value for a key.
+func (sm *SyncSortedMap[K, V]) Set(key K, value V) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.items.Set(key, value)
+}
+
+// Get returns the value and true if the key exists in the map, otherwise it returns the zero value and false.
+func (sm *SyncSortedMap[K, V]) Get(key K) (V, bool) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return sm.items.Get(key)
+}
+
+// Has returns true if the key exists in the map.
+func (sm *SyncSortedMap[K, V]) Has(key K) bool {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return sm.items.Has(key)
+}
+
+// Delete deletes the value for a key. If the key does not exist, it does nothing.
+func (sm *SyncSortedMap[K, V]) Delete(key K) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.items.Delete(key)
+}
+
+// Len returns the number of entries.
+func (sm *SyncSortedMap[K, V]) Len() int {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return sm.items.Len()
+}
+
+// First returns the smallest key and its value. ok is false if the map is empty.
+func (sm *SyncSortedMap[K, V]) First() (K, V, bool) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return sm.items.First()
+}
+
+// Last returns the largest key and its value. ok is false if the map is empty.
+func (sm *SyncSortedMap[K, V]) Last() (K, V, bool) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return sm.items.Last()
+}
+
+// Floor returns the largest key less than or equal to the given key and its value. ok is false if there is none.
+func (sm *SyncSortedMap[K, V]) Floor(key K) (K, V, bool) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return sm.items.Floor(key)
+}
+
+// Ceiling returns the smallest key greater than or equal to the given key and its value. ok is false if there is none.
+func (sm *SyncSortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return sm.items.Ceiling(key)
+}
+
+// Range returns an iterator over a snapshot of the keys in [from, to) and their values, in ascending order.
+func (sm *SyncSortedMap[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return sm.snapshot(func() iter.Seq2[K, V] { return sm.items.Range(from, to) })
+}
+
+// All returns an iterator over a snapshot of the keys and values in ascending order.
+func (sm *SyncSortedMap[K, V]) All() iter.Seq2[K, V] {
+	return sm.snapshot(sm.items.All)
+}
+
+func (sm *SyncSortedMap[K, V]) snapshot(seq func() iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		type entry struct {
+			key   K
+			value V
+		}
+
+		var entries []entry
+
+		sm.mutex.RLock()
+		for k, v := range seq() {
+			entries = append(entries, entry{key: k, value: v})
+		}
+		sm.mutex.RUnlock()
+
+		for _, e := range entries {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
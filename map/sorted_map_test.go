@@ -0,0 +1,247 @@
+package m
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedMap(t *testing.T) {
+	t.Run("map semantics", func(t *testing.T) {
+		sm := NewSortedMap[string, int]()
+		sm.Set("b", 2)
+		sm.Set("a", 1)
+		sm.Set("c", 3)
+		sm.Set("b", 20)
+
+		v, ok := sm.Get("b")
+		assert.True(t, ok)
+		assert.Equal(t, 20, v)
+		assert.True(t, sm.Has("a"))
+		assert.False(t, sm.Has("d"))
+		assert.Equal(t, 3, sm.Len())
+		assert.Equal(t, []string{"a", "b", "c"}, sm.Keys())
+		assert.Equal(t, []int{1, 20, 3}, sm.Values())
+
+		sm.Delete("a")
+		sm.Delete("missing")
+
+		_, ok = sm.Get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 2, sm.Len())
+		assert.Equal(t, []string{"b", "c"}, sm.Keys())
+	})
+
+	t.Run("First and Last", func(t *testing.T) {
+		sm := NewSortedMap[int, string]()
+
+		_, _, ok := sm.First()
+		assert.False(t, ok)
+		_, _, ok = sm.Last()
+		assert.False(t, ok)
+
+		for _, k := range []int{5, 1, 9, 3} {
+			sm.Set(k, "v")
+		}
+
+		k, _, ok := sm.First()
+		assert.True(t, ok)
+		assert.Equal(t, 1, k)
+
+		k, _, ok = sm.Last()
+		assert.True(t, ok)
+		assert.Equal(t, 9, k)
+	})
+
+	t.Run("Floor and Ceiling", func(t *testing.T) {
+		sm := NewSortedMap[int, string]()
+		sm.Set(10, "a")
+		sm.Set(20, "b")
+		sm.Set(30, "c")
+
+		for _, tc := range []struct {
+			key         int
+			floor, ceil int
+			hasFloor    bool
+			hasCeil     bool
+		}{
+			{key: 5, ceil: 10, hasCeil: true},
+			{key: 10, floor: 10, ceil: 10, hasFloor: true, hasCeil: true},
+			{key: 15, floor: 10, ceil: 20, hasFloor: true, hasCeil: true},
+			{key: 30, floor: 30, ceil: 30, hasFloor: true, hasCeil: true},
+			{key: 35, floor: 30, hasFloor: true},
+		} {
+			k, _, ok := sm.Floor(tc.key)
+			assert.Equal(t, tc.hasFloor, ok, "floor %d", tc.key)
+			assert.Equal(t, tc.floor, k, "floor %d", tc.key)
+
+			k, _, ok = sm.Ceiling(tc.key)
+			assert.Equal(t, tc.hasCeil, ok, "ceiling %d", tc.key)
+			assert.Equal(t, tc.ceil, k, "ceiling %d", tc.key)
+		}
+
+		_, v, _ := sm.Floor(25)
+		assert.Equal(t, "b", v)
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		sm := NewSortedMap[int, int]()
+		for i := range 10 {
+			sm.Set(i*10, i)
+		}
+
+		assert.Equal(t, []int{20, 30, 40}, slices.Collect(keys(sm.Range(20, 50))))
+		assert.Equal(t, []int{20, 30, 40, 50}, slices.Collect(keys(sm.Range(15, 51))))
+		assert.Empty(t, slices.Collect(keys(sm.Range(50, 50))))
+		assert.Empty(t, slices.Collect(keys(sm.Range(50, 10))))
+		assert.Equal(t, sm.Keys(), slices.Collect(keys(sm.Range(-1, 1000))))
+
+		n := 0
+		for range sm.Range(0, 100) {
+			n++
+
+			break
+		}
+
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("custom comparator", func(t *testing.T) {
+		sm := NewSortedMapFunc[string, int](func(a, b string) int {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		})
+		sm.Set("b", 1)
+		sm.Set("A", 2)
+		sm.Set("B", 3)
+
+		assert.Equal(t, []string{"A", "b"}, sm.Keys())
+		assert.Equal(t, []int{2, 3}, sm.Values())
+	})
+
+	t.Run("matches a sorted builtin map", func(t *testing.T) {
+		rnd := rand.New(rand.NewPCG(5, 6))
+		expected := map[int]int{}
+		sm := NewSortedMap[int, int]()
+
+		for i := range 5000 {
+			key := rnd.IntN(1000)
+			if rnd.IntN(3) == 0 {
+				delete(expected, key)
+				sm.Delete(key)
+			} else {
+				expected[key] = i
+				sm.Set(key, i)
+			}
+		}
+
+		sorted := slices.Sorted(keys(All(expected)))
+		assert.Equal(t, sorted, sm.Keys())
+		assert.Equal(t, len(expected), sm.Len())
+		assert.Equal(t, expected, Collect(sm.All()))
+
+		for range 200 {
+			key := rnd.IntN(1100) - 50
+			i, found := slices.BinarySearch(sorted, key)
+
+			k, _, ok := sm.Ceiling(key)
+			assert.Equal(t, i < len(sorted), ok)
+			if ok {
+				assert.Equal(t, sorted[i], k)
+			}
+
+			if !found {
+				i--
+			}
+
+			k, _, ok = sm.Floor(key)
+			assert.Equal(t, i >= 0, ok)
+			if ok {
+				assert.Equal(t, sorted[i], k)
+			}
+		}
+	})
+}
+
+func TestSyncSortedMap(t *testing.T) {
+	t.Run("map semantics", func(t *testing.T) {
+		sm := NewSyncSortedMap[int, string]()
+		sm.Set(2, "b")
+		sm.Set(1, "a")
+		sm.Set(3, "c")
+		sm.Delete(3)
+
+		v, ok := sm.Get(1)
+		assert.True(t, ok)
+		assert.Equal(t, "a", v)
+		assert.True(t, sm.Has(2))
+		assert.Equal(t, 2, sm.Len())
+
+		k, _, _ := sm.First()
+		assert.Equal(t, 1, k)
+		k, _, _ = sm.Last()
+		assert.Equal(t, 2, k)
+		k, _, _ = sm.Floor(5)
+		assert.Equal(t, 2, k)
+		k, _, _ = sm.Ceiling(0)
+		assert.Equal(t, 1, k)
+
+		assert.Equal(t, []int{2}, slices.Collect(keys(sm.Range(2, 3))))
+	})
+
+	t.Run("iteration may modify the map", func(t *testing.T) {
+		sm := NewSyncSortedMapFunc[int, int](func(a, b int) int { return b - a })
+		for i := range 5 {
+			sm.Set(i, i)
+		}
+
+		var seen []int
+		for k := range sm.All() {
+			seen = append(seen, k)
+			sm.Delete(k)
+		}
+
+		assert.Equal(t, []int{4, 3, 2, 1, 0}, seen)
+		assert.Equal(t, 0, sm.Len())
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		sm := NewSyncSortedMap[int, int]()
+
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := range 200 {
+					sm.Set(g*1000+i, i)
+					sm.Floor(g * 1000)
+					if i%2 == 0 {
+						sm.Delete(g*1000 + i)
+					}
+
+					for range sm.Range(g*1000, g*1000+10) {
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 800, sm.Len())
+		assert.True(t, slices.IsSorted(slices.Collect(keys(sm.All()))))
+	})
+}
+
+func keys[K, V any](seq func(yield func(K, V) bool)) func(yield func(K) bool) {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
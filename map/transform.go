@@ -0,0 +1,189 @@
+package m
+
+/*
+MapValues returns a new map with the result of applying the function to each value.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2}
+	result := m.MapValues(input, func(v int) string { return strconv.Itoa(v * 2) })
+	// result == map[string]string{"a": "2", "b": "4"}
+*/
+func MapValues[K comparable, V, R any](input map[K]V, fn func(V) R) map[K]R {
+	result := make(map[K]R, len(input))
+	for k, v := range input {
+		result[k] = fn(v)
+	}
+
+	return result
+}
+
+/*
+MapKeys returns a new map with the result of applying the function to each key. When several keys map to the same
+new key, onCollision decides which value is kept. If onCollision is nil, one of the values is kept arbitrarily,
+since map iteration order is random.
+
+Example
+
+	input := map[string]int{"a": 1, "A": 2, "b": 3}
+	sum := func(key string, existing, incoming int) int { return existing + incoming }
+	result := m.MapKeys(input, strings.ToUpper, sum)
+	// result == map[string]int{"A": 3, "B": 3}
+*/
+func MapKeys[K, R comparable, V any](input map[K]V, fn func(K) R, onCollision func(key R, existing, incoming V) V) map[R]V {
+	result := make(map[R]V, len(input))
+	for k, v := range input {
+		key := fn(k)
+		if existing, ok := result[key]; ok && onCollision != nil {
+			v = onCollision(key, existing, v)
+		}
+
+		result[key] = v
+	}
+
+	return result
+}
+
+/*
+Filter returns a new map containing only the entries that satisfy the given predicate.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2, "c": 3}
+	result := m.Filter(input, func(key string, value int) bool { return value%2 == 1 })
+	// result == map[string]int{"a": 1, "c": 3}
+*/
+func Filter[K comparable, V any](input map[K]V, fn func(key K, value V) bool) map[K]V {
+	result := make(map[K]V)
+	for k, v := range input {
+		if fn(k, v) {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+/*
+Invert returns a new map with the keys and values swapped. If several keys share a value, one of them is kept
+arbitrarily; use InvertMulti to keep all of them.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2}
+	result := m.Invert(input)
+	// result == map[int]string{1: "a", 2: "b"}
+*/
+func Invert[K, V comparable](input map[K]V) map[V]K {
+	result := make(map[V]K, len(input))
+	for k, v := range input {
+		result[v] = k
+	}
+
+	return result
+}
+
+/*
+InvertMulti returns a new map from each value to all the keys that have it. The order of the keys is unspecified.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2, "c": 1}
+	result := m.InvertMulti(input)
+	// result == map[int][]string{1: {"a", "c"}, 2: {"b"}}
+*/
+func InvertMulti[K, V comparable](input map[K]V) map[V][]K {
+	result := make(map[V][]K)
+	for k, v := range input {
+		result[v] = append(result[v], k)
+	}
+
+	return result
+}
+
+/*
+Merge merges multiple maps into a new map. When a key is present in several maps, onConflict is called with the value
+merged so far and the value of the later map. If onConflict is nil, the value of the later map wins.
+
+Example
+
+	defaults := map[string]int{"a": 1, "b": 2}
+	overrides := map[string]int{"b": 3}
+	result := m.Merge(nil, defaults, overrides)
+	// result == map[string]int{"a": 1, "b": 3}
+*/
+func Merge[K comparable, V any](onConflict func(key K, existing, incoming V) V, maps ...map[K]V) map[K]V {
+	result := make(map[K]V)
+	for _, input := range maps {
+		for k, v := range input {
+			if existing, ok := result[k]; ok && onConflict != nil {
+				v = onConflict(k, existing, v)
+			}
+
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+/*
+Pick returns a new map containing only the given keys. Keys that are not in the map are ignored.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2, "c": 3}
+	result := m.Pick(input, "a", "c", "d")
+	// result == map[string]int{"a": 1, "c": 3}
+*/
+func Pick[K comparable, V any](input map[K]V, keys ...K) map[K]V {
+	result := make(map[K]V, len(keys))
+	for _, k := range keys {
+		if v, ok := input[k]; ok {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+/*
+Omit returns a new map containing all but the given keys.
+
+Example
+
+	input := map[string]int{"a": 1, "b": 2, "c": 3}
+	result := m.Omit(input, "a", "c")
+	// result == map[string]int{"b": 2}
+*/
+func Omit[K comparable, V any](input map[K]V, keys ...K) map[K]V {
+	result := make(map[K]V, len(input))
+	for k, v := range input {
+		result[k] = v
+	}
+
+	for _, k := range keys {
+		delete(result, k)
+	}
+
+	return result
+}
+
+/*
+GroupBy groups the items of a slice by the key returned by the function. Items keep their relative order.
+
+Example
+
+	input := []string{"apple", "avocado", "banana"}
+	result := m.GroupBy(input, func(s string) byte { return s[0] })
+	// result == map[byte][]string{'a': {"apple", "avocado"}, 'b': {"banana"}}
+*/
+func GroupBy[T any, K comparable](items []T, fn func(T) K) map[K][]T {
+	result := make(map[K][]T)
+	for _, item := range items {
+		key := fn(item)
+		result[key] = append(result[key], item)
+	}
+
+	return result
+}
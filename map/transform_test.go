@@ -0,0 +1,76 @@
+package m
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransform(t *testing.T) {
+	t.Run("MapValues", func(t *testing.T) {
+		m := map[string]int{"a": 1, "b": 2}
+		result := MapValues(m, func(v int) string { return strconv.Itoa(v * 2) })
+
+		assert.Equal(t, map[string]string{"a": "2", "b": "4"}, result)
+	})
+
+	t.Run("MapKeys", func(t *testing.T) {
+		m := map[string]int{"a": 1, "A": 2, "b": 3}
+		sum := func(_ string, existing, incoming int) int { return existing + incoming }
+
+		assert.Equal(t, map[string]int{"A": 3, "B": 3}, MapKeys(m, strings.ToUpper, sum))
+
+		result := MapKeys(m, strings.ToUpper, nil)
+		assert.Len(t, result, 2)
+		assert.Contains(t, []int{1, 2}, result["A"])
+
+		m2 := map[int]string{1: "a", 2: "b"}
+		assert.Equal(t, map[string]string{"1": "a", "2": "b"}, MapKeys(m2, strconv.Itoa, nil))
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+		assert.Equal(t, map[string]int{"a": 1, "c": 3}, Filter(m, func(_ string, v int) bool { return v%2 == 1 }))
+		assert.Equal(t, map[string]int{"b": 2}, Filter(m, func(k string, _ int) bool { return k == "b" }))
+	})
+
+	t.Run("Invert", func(t *testing.T) {
+		m := map[string]int{"a": 1, "b": 2}
+		assert.Equal(t, map[int]string{1: "a", 2: "b"}, Invert(m))
+
+		m2 := map[string]int{"a": 1, "b": 2, "c": 1}
+		result := InvertMulti(m2)
+		assert.ElementsMatch(t, []string{"a", "c"}, result[1])
+		assert.Equal(t, []string{"b"}, result[2])
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		defaults := map[string]int{"a": 1, "b": 2}
+		overrides := map[string]int{"b": 3, "c": 4}
+
+		assert.Equal(t, map[string]int{"a": 1, "b": 3, "c": 4}, Merge(nil, defaults, overrides))
+
+		sum := func(_ string, existing, incoming int) int { return existing + incoming }
+		assert.Equal(t, map[string]int{"a": 1, "b": 5, "c": 4}, Merge(sum, defaults, overrides))
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, defaults)
+		assert.Empty(t, Merge[string, int](nil))
+	})
+
+	t.Run("Pick/Omit", func(t *testing.T) {
+		m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+		assert.Equal(t, map[string]int{"a": 1, "c": 3}, Pick(m, "a", "c", "d"))
+		assert.Equal(t, map[string]int{"b": 2}, Omit(m, "a", "c", "d"))
+		assert.Len(t, m, 3)
+	})
+
+	t.Run("GroupBy", func(t *testing.T) {
+		input := []string{"apple", "banana", "avocado"}
+		result := GroupBy(input, func(s string) byte { return s[0] })
+
+		assert.Equal(t, map[byte][]string{'a': {"apple", "avocado"}, 'b': {"banana"}}, result)
+	})
+}
@@ -0,0 +1,205 @@
+package m
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// EventKind is the kind of an Event.
+type EventKind int
+
+const (
+	// EventPut means a value was stored for the key.
+	EventPut EventKind = iota
+	// EventDelete means the key was deleted.
+	EventDelete
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventPut:
+		return "put"
+	case EventDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a change of a key in a watched map. Old is the previous value if Loaded is true; New is zero for deletes.
+type Event[T comparable, V any] struct {
+	Key    T
+	Old    V
+	New    V
+	Kind   EventKind
+	Loaded bool
+}
+
+// WatchPolicy decides what happens when a watcher's buffer is full.
+type WatchPolicy int
+
+const (
+	// WatchDrop drops the event and counts it in Dropped. Writers never wait for watchers.
+	WatchDrop WatchPolicy = iota
+	// WatchBlock makes the writer wait until the watcher receives the event or is closed.
+	// Writers of the whole map are blocked meanwhile, so the goroutine receiving the events must not write to the map.
+	WatchBlock
+)
+
+/*
+WatchOptions configures a Watcher.
+
+Example
+
+	opts := m.WatchOptions[string]{
+		Filter: m.WatchPrefix[string]("user/"),
+		Buffer: 128,
+		Policy: m.WatchBlock,
+	}
+*/
+type WatchOptions[T comparable] struct {
+	// Filter selects the keys to watch. Defaults to all keys.
+	Filter func(key T) bool
+	// Buffer is the capacity of the events channel. Defaults to 64.
+	Buffer int
+	// Policy decides what happens when the buffer is full. Defaults to WatchDrop.
+	Policy WatchPolicy
+}
+
+/*
+WatchKey returns a filter matching a single key.
+
+Example
+
+	watcher := asyncMap.Watch(m.WatchOptions[string]{Filter: m.WatchKey("config")})
+*/
+func WatchKey[T comparable](key T) func(T) bool {
+	return func(k T) bool {
+		return k == key
+	}
+}
+
+/*
+WatchPrefix returns a filter matching the keys starting with prefix.
+
+Example
+
+	watcher := asyncMap.Watch(m.WatchOptions[string]{Filter: m.WatchPrefix[string]("user/")})
+*/
+func WatchPrefix[T ~string](prefix string) func(T) bool {
+	return func(k T) bool {
+		return strings.HasPrefix(string(k), prefix)
+	}
+}
+
+// Watcher receives the events of a map. It is created by AsyncMap.Watch.
+type Watcher[T comparable, V any] struct {
+	hub     *watchHub[T, V]
+	opts    WatchOptions[T]
+	events  chan Event[T, V]
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// Events returns the channel of events. It is closed by Close.
+func (w *Watcher[T, V]) Events() <-chan Event[T, V] {
+	return w.events
+}
+
+// Dropped returns the number of events dropped because the buffer was full.
+func (w *Watcher[T, V]) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Close stops the watcher and closes its events channel. It is safe to call Close more than once.
+func (w *Watcher[T, V]) Close() {
+	w.once.Do(func() {
+		// Closing done first releases a writer blocked on this watcher, which holds the hub lock.
+		close(w.done)
+
+		w.hub.mutex.Lock()
+		delete(w.hub.watchers, w)
+		w.hub.count.Add(-1)
+		w.hub.mutex.Unlock()
+
+		close(w.events)
+	})
+}
+
+func (w *Watcher[T, V]) send(event Event[T, V]) {
+	if w.opts.Filter != nil && !w.opts.Filter(event.Key) {
+		return
+	}
+
+	if w.opts.Policy == WatchBlock {
+		select {
+		case w.events <- event:
+		case <-w.done:
+		}
+
+		return
+	}
+
+	select {
+	case w.events <- event:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// watchHub holds the watchers of a map. While watchers exist, writes hold mutex so that events are published in order.
+type watchHub[T comparable, V any] struct {
+	watchers map[*Watcher[T, V]]struct{}
+	count    atomic.Int32
+	mutex    sync.Mutex
+}
+
+// publish sends the event to every watcher. The caller must hold the hub lock.
+func (h *watchHub[T, V]) publish(event Event[T, V]) {
+	for w := range h.watchers {
+		w.send(event)
+	}
+}
+
+/*
+Watch returns a Watcher receiving the events of the keys selected by opts.Filter, in the order of the writes.
+While watchers exist, writes to the map are serialised. Call Close when done watching.
+
+Example
+
+	watcher := asyncMap.Watch(m.WatchOptions[string]{Filter: m.WatchKey("config")})
+	defer watcher.Close()
+	for event := range watcher.Events() {
+		fmt.Printf("%s %s: %v -> %v", event.Kind, event.Key, event.Old, event.New)
+	}
+*/
+func (m *AsyncMap[T, V]) Watch(opts WatchOptions[T]) *Watcher[T, V] {
+	if opts.Buffer < 0 {
+		panic("buffer must be greater than or equal to 0")
+	}
+
+	if opts.Buffer == 0 {
+		opts.Buffer = 64
+	}
+
+	w := &Watcher[T, V]{
+		hub:    &m.hub,
+		opts:   opts,
+		events: make(chan Event[T, V], opts.Buffer),
+		done:   make(chan struct{}),
+	}
+
+	m.hub.mutex.Lock()
+	defer m.hub.mutex.Unlock()
+
+	if m.hub.watchers == nil {
+		m.hub.watchers = make(map[*Watcher[T, V]]struct{})
+	}
+
+	m.hub.watchers[w] = struct{}{}
+	m.hub.count.Add(1)
+
+	return w
+}
@@ -0,0 +1,200 @@
+package m
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func drain[T comparable, V any](w *Watcher[T, V]) []Event[T, V] {
+	var events []Event[T, V]
+	for {
+		select {
+		case event := <-w.Events():
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("publishes puts and deletes with old and new values", func(t *testing.T) {
+		asyncMap := NewAsyncMap[string, int]()
+		asyncMap.Store("existing", 1)
+
+		w := asyncMap.Watch(WatchOptions[string]{})
+		defer w.Close()
+
+		asyncMap.Store("a", 1)
+		asyncMap.Store("a", 2)
+		asyncMap.LoadOrStore("a", 3)
+		asyncMap.LoadOrStore("b", 4)
+		asyncMap.Swap("b", 5)
+		CompareAndSwap(asyncMap, "b", 5, 6)
+		CompareAndSwap(asyncMap, "b", 5, 7)
+		asyncMap.Compute("c", func(int, bool) (int, bool) { return 8, true })
+		asyncMap.ComputeIfPresent("c", func(v int) (int, bool) { return v + 1, true })
+		asyncMap.LoadOrCompute("d", func() int { return 10 })
+		asyncMap.Delete("a")
+		asyncMap.Delete("missing")
+		asyncMap.LoadAndDelete("b")
+		CompareAndDelete(asyncMap, "c", 9)
+		asyncMap.ComputeIfPresent("d", func(int) (int, bool) { return 0, false })
+
+		assert.Equal(t, []Event[string, int]{
+			{Key: "a", New: 1, Kind: EventPut},
+			{Key: "a", Old: 1, New: 2, Kind: EventPut, Loaded: true},
+			{Key: "b", New: 4, Kind: EventPut},
+			{Key: "b", Old: 4, New: 5, Kind: EventPut, Loaded: true},
+			{Key: "b", Old: 5, New: 6, Kind: EventPut, Loaded: true},
+			{Key: "c", New: 8, Kind: EventPut},
+			{Key: "c", Old: 8, New: 9, Kind: EventPut, Loaded: true},
+			{Key: "d", New: 10, Kind: EventPut},
+			{Key: "a", Old: 2, Kind: EventDelete, Loaded: true},
+			{Key: "b", Old: 6, Kind: EventDelete, Loaded: true},
+			{Key: "c", Old: 9, Kind: EventDelete, Loaded: true},
+			{Key: "d", Old: 10, Kind: EventDelete, Loaded: true},
+		}, drain(w))
+	})
+
+	t.Run("Clear and Restore", func(t *testing.T) {
+		asyncMap := NewAsyncMap[string, int]()
+		asyncMap.Store("a", 1)
+
+		var buf bytes.Buffer
+		assert.NoError(t, asyncMap.Snapshot(&buf, nil))
+
+		w := asyncMap.Watch(WatchOptions[string]{})
+		defer w.Close()
+
+		asyncMap.Clear()
+		asyncMap.Store("b", 2)
+		assert.NoError(t, asyncMap.Restore(&buf, nil))
+
+		assert.Equal(t, []Event[string, int]{
+			{Key: "a", Old: 1, Kind: EventDelete, Loaded: true},
+			{Key: "b", New: 2, Kind: EventPut},
+			{Key: "b", Old: 2, Kind: EventDelete, Loaded: true},
+			{Key: "a", New: 1, Kind: EventPut},
+		}, drain(w))
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		asyncMap := NewAsyncMap[string, int]()
+		key := asyncMap.Watch(WatchOptions[string]{Filter: WatchKey("config")})
+		defer key.Close()
+
+		prefix := asyncMap.Watch(WatchOptions[string]{Filter: WatchPrefix[string]("user/")})
+		defer prefix.Close()
+
+		asyncMap.Store("config", 1)
+		asyncMap.Store("user/ann", 2)
+		asyncMap.Store("other", 3)
+
+		assert.Equal(t, []Event[string, int]{{Key: "config", New: 1, Kind: EventPut}}, drain(key))
+		assert.Equal(t, []Event[string, int]{{Key: "user/ann", New: 2, Kind: EventPut}}, drain(prefix))
+	})
+
+	t.Run("drop policy", func(t *testing.T) {
+		asyncMap := NewAsyncMap[int, int]()
+		w := asyncMap.Watch(WatchOptions[int]{Buffer: 2, Policy: WatchDrop})
+		defer w.Close()
+
+		for i := range 5 {
+			asyncMap.Store(i, i)
+		}
+
+		assert.Len(t, drain(w), 2)
+		assert.Equal(t, uint64(3), w.Dropped())
+	})
+
+	t.Run("block policy", func(t *testing.T) {
+		asyncMap := NewAsyncMap[int, int]()
+		w := asyncMap.Watch(WatchOptions[int]{Buffer: 1, Policy: WatchBlock})
+		defer w.Close()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			for i := range 3 {
+				asyncMap.Store(i, i)
+			}
+		}()
+
+		var keys []int
+		for range 3 {
+			keys = append(keys, (<-w.Events()).Key)
+		}
+
+		<-done
+		assert.Equal(t, []int{0, 1, 2}, keys)
+		assert.Equal(t, uint64(0), w.Dropped())
+	})
+
+	t.Run("Close releases blocked writers", func(t *testing.T) {
+		asyncMap := NewAsyncMap[int, int]()
+		w := asyncMap.Watch(WatchOptions[int]{Buffer: 1, Policy: WatchBlock})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			asyncMap.Store(1, 1)
+			asyncMap.Store(2, 2)
+		}()
+
+		assert.Eventually(t, func() bool { return len(w.Events()) == 1 }, time.Second, time.Millisecond)
+		w.Close()
+		w.Close()
+		<-done
+
+		_, ok := asyncMap.Load(2)
+		assert.True(t, ok)
+
+		<-w.Events()
+		_, open := <-w.Events()
+		assert.False(t, open)
+	})
+
+	t.Run("concurrent writers publish in order", func(t *testing.T) {
+		asyncMap := NewAsyncMap[string, int]()
+		w := asyncMap.Watch(WatchOptions[string]{Buffer: 1000})
+		defer w.Close()
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for range 50 {
+					asyncMap.Compute("counter", func(v int, _ bool) (int, bool) { return v + 1, true })
+				}
+			}()
+		}
+		wg.Wait()
+
+		events := drain(w)
+		assert.Len(t, events, 500)
+
+		for i, event := range events {
+			assert.Equal(t, i+1, event.New)
+			assert.Equal(t, i, event.Old)
+		}
+	})
+
+	t.Run("invalid buffer", func(t *testing.T) {
+		assert.Panics(t, func() { NewAsyncMap[int, int]().Watch(WatchOptions[int]{Buffer: -1}) })
+	})
+
+	t.Run("EventKind String", func(t *testing.T) {
+		assert.Equal(t, "put", EventPut.String())
+		assert.Equal(t, "delete", EventDelete.String())
+		assert.Equal(t, "unknown", EventKind(42).String())
+	})
+}
@@ -0,0 +1,324 @@
+package result
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a CircuitBreaker that short-circuits a call.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+const (
+	defaultFailureThreshold = 5
+	defaultCoolDown         = 30 * time.Second
+)
+
+// State is the state of a CircuitBreaker.
+type State int
+
+const (
+	// StateClosed lets all calls through and counts consecutive failures.
+	StateClosed State = iota
+	// StateOpen short-circuits all calls with ErrCircuitOpen until the cool-down has passed.
+	StateOpen
+	// StateHalfOpen lets a limited number of trial calls through to probe the dependency.
+	StateHalfOpen
+)
+
+// outcome is how a call is recorded by a CircuitBreaker.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored is an error rejected by IsFailure; it only releases the half-open slot of the call.
+	outcomeIgnored
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+/*
+BreakerSettings configures a CircuitBreaker. Zero values fall back to sensible defaults.
+
+Example
+
+	settings := result.BreakerSettings{
+		FailureThreshold: 3,
+		CoolDown:         10 * time.Second,
+		OnStateChange: func(from, to result.State) {
+			log.Printf("circuit breaker %s -> %s", from, to)
+		},
+	}
+*/
+type BreakerSettings struct {
+	// Clock is used to measure the cool-down. Defaults to the system clock.
+	Clock Clock
+	// OnStateChange is called after every state transition.
+	OnStateChange func(from, to State)
+	// IsFailure reports whether an error counts as a failure. Errors it rejects are ignored: they count neither as
+	// failures nor as successes. Defaults to every error but context.Canceled.
+	IsFailure func(err error) bool
+	// FailureThreshold is the number of consecutive failures that opens the circuit. Defaults to 5.
+	FailureThreshold int
+	// SuccessThreshold is the number of consecutive half-open successes that closes the circuit. Defaults to 1.
+	SuccessThreshold int
+	// HalfOpenMaxCalls is the number of concurrent trial calls allowed while half-open. Defaults to 1.
+	HalfOpenMaxCalls int
+	// CoolDown is how long the circuit stays open before it becomes half-open. Defaults to 30s.
+	CoolDown time.Duration
+}
+
+/*
+CircuitBreaker stops calling a failing dependency for a cool-down period once a number of consecutive
+failures has been reached, and probes it again with a limited number of trial calls afterwards.
+
+Example
+
+	breaker := result.NewCircuitBreaker(result.BreakerSettings{FailureThreshold: 3})
+	group, ctx := result.WithErrorsThreshold[int](ctx, 2)
+	group.Go(result.WithBreaker(breaker, func() ([]int, error) {
+		return callDependency(ctx)
+	}))
+*/
+type CircuitBreaker struct {
+	openedAt   time.Time
+	settings   BreakerSettings
+	mutex      sync.Mutex
+	state      State
+	generation uint64
+	failures   int
+	successes  int
+	inFlight   int
+}
+
+// NewCircuitBreaker returns a new pointer to a closed CircuitBreaker.
+func NewCircuitBreaker(settings BreakerSettings) *CircuitBreaker {
+	if settings.FailureThreshold < 0 || settings.SuccessThreshold < 0 || settings.HalfOpenMaxCalls < 0 {
+		panic("thresholds must be greater than or equal to 0")
+	}
+
+	if settings.Clock == nil {
+		settings.Clock = SystemClock{}
+	}
+
+	if settings.IsFailure == nil {
+		settings.IsFailure = func(err error) bool {
+			return !errors.Is(err, context.Canceled)
+		}
+	}
+
+	if settings.FailureThreshold == 0 {
+		settings.FailureThreshold = defaultFailureThreshold
+	}
+
+	if settings.SuccessThreshold == 0 {
+		settings.SuccessThreshold = 1
+	}
+
+	if settings.HalfOpenMaxCalls == 0 {
+		settings.HalfOpenMaxCalls = 1
+	}
+
+	if settings.CoolDown == 0 {
+		settings.CoolDown = defaultCoolDown
+	}
+
+	return &CircuitBreaker{settings: settings}
+}
+
+/*
+State returns the current state of the circuit breaker.
+
+Example
+
+	breaker := result.NewCircuitBreaker(result.BreakerSettings{})
+	breaker.State() // result.StateClosed
+*/
+func (cb *CircuitBreaker) State() State {
+	cb.mutex.Lock()
+	state, from, changed := cb.currentState()
+	cb.mutex.Unlock()
+
+	if changed {
+		cb.notify(from, state)
+	}
+
+	return state
+}
+
+/*
+Execute runs fn if the circuit breaker allows it and records the outcome. It returns ErrCircuitOpen
+without calling fn if the circuit is open or the half-open trial calls are exhausted. If fn panics, the
+panic is recorded as a failure and propagated.
+
+Example
+
+	err := breaker.Execute(func() error {
+		return callDependency(ctx)
+	})
+	if errors.Is(err, result.ErrCircuitOpen) {
+		// the dependency is known to be down
+	}
+*/
+func (cb *CircuitBreaker) Execute(fn func() error) error {
+	generation, err := cb.before()
+	if err != nil {
+		return err
+	}
+
+	// A panicking fn counts as a failure, so that a half-open trial call cannot leak its slot.
+	result := outcomeFailure
+	defer func() { cb.after(generation, result) }()
+
+	err = fn()
+
+	switch {
+	case err == nil:
+		result = outcomeSuccess
+	case !cb.settings.IsFailure(err):
+		result = outcomeIgnored
+	}
+
+	return err
+}
+
+/*
+WithBreaker wraps a task function so that it is short-circuited with ErrCircuitOpen by the breaker.
+
+Example
+
+	group.Go(result.WithBreaker(breaker, func() ([]int, error) {
+		return []int{1, 2, 3}, nil
+	}))
+*/
+func WithBreaker[T any](cb *CircuitBreaker, f func() ([]T, error)) func() ([]T, error) {
+	return func() ([]T, error) {
+		var res []T
+
+		err := cb.Execute(func() error {
+			var err error
+			res, err = f()
+
+			return err
+		})
+
+		return res, err
+	}
+}
+
+func (cb *CircuitBreaker) before() (uint64, error) {
+	cb.mutex.Lock()
+	state, from, changed := cb.currentState()
+
+	var err error
+
+	switch state {
+	case StateOpen:
+		err = ErrCircuitOpen
+	case StateHalfOpen:
+		if cb.inFlight >= cb.settings.HalfOpenMaxCalls {
+			err = ErrCircuitOpen
+		} else {
+			cb.inFlight++
+		}
+	case StateClosed:
+	}
+
+	generation := cb.generation
+	cb.mutex.Unlock()
+
+	if changed {
+		cb.notify(from, state)
+	}
+
+	return generation, err
+}
+
+func (cb *CircuitBreaker) after(generation uint64, result outcome) {
+	cb.mutex.Lock()
+
+	if generation != cb.generation {
+		cb.mutex.Unlock()
+
+		return
+	}
+
+	from := cb.state
+
+	switch cb.state {
+	case StateClosed:
+		switch result {
+		case outcomeSuccess:
+			cb.failures = 0
+		case outcomeFailure:
+			cb.failures++
+			if cb.failures >= cb.settings.FailureThreshold {
+				cb.setState(StateOpen)
+			}
+		case outcomeIgnored:
+		}
+	case StateHalfOpen:
+		cb.inFlight--
+
+		switch result {
+		case outcomeSuccess:
+			cb.successes++
+			if cb.successes >= cb.settings.SuccessThreshold {
+				cb.setState(StateClosed)
+			}
+		case outcomeFailure:
+			cb.setState(StateOpen)
+		case outcomeIgnored:
+		}
+	case StateOpen:
+	}
+
+	to := cb.state
+	cb.mutex.Unlock()
+
+	if from != to {
+		cb.notify(from, to)
+	}
+}
+
+// currentState moves an open circuit to half-open once the cool-down has passed. It must be called with the mutex held.
+func (cb *CircuitBreaker) currentState() (state, from State, changed bool) {
+	if cb.state == StateOpen && !cb.settings.Clock.Now().Before(cb.openedAt.Add(cb.settings.CoolDown)) {
+		cb.setState(StateHalfOpen)
+
+		return StateHalfOpen, StateOpen, true
+	}
+
+	return cb.state, cb.state, false
+}
+
+func (cb *CircuitBreaker) setState(state State) {
+	cb.state = state
+	cb.generation++
+	cb.failures = 0
+	cb.successes = 0
+	cb.inFlight = 0
+
+	if state == StateOpen {
+		cb.openedAt = cb.settings.Clock.Now()
+	}
+}
+
+func (cb *CircuitBreaker) notify(from, to State) {
+	if cb.settings.OnStateChange != nil {
+		cb.settings.OnStateChange(from, to)
+	}
+}
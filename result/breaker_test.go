@@ -0,0 +1,206 @@
+package result
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neurocode-io/go-pkgs/result/resulttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		cb := NewCircuitBreaker(BreakerSettings{FailureThreshold: 2, Clock: resulttest.NewFakeClock(time.Time{})})
+
+		assert.ErrorIs(t, cb.Execute(func() error { return err1 }), err1)
+		assert.NoError(t, cb.Execute(func() error { return nil }))
+		assert.ErrorIs(t, cb.Execute(func() error { return err1 }), err1)
+		assert.Equal(t, StateClosed, cb.State())
+
+		assert.ErrorIs(t, cb.Execute(func() error { return err2 }), err2)
+		assert.Equal(t, StateOpen, cb.State())
+
+		called := false
+		err := cb.Execute(func() error {
+			called = true
+
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.False(t, called)
+	})
+
+	t.Run("half-open after cool-down", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(time.Time{})
+		cb := NewCircuitBreaker(BreakerSettings{FailureThreshold: 1, CoolDown: time.Minute, Clock: clock})
+
+		_ = cb.Execute(func() error { return err1 })
+		assert.Equal(t, StateOpen, cb.State())
+
+		clock.Advance(59 * time.Second)
+		assert.Equal(t, StateOpen, cb.State())
+
+		clock.Advance(time.Second)
+		assert.Equal(t, StateHalfOpen, cb.State())
+
+		assert.NoError(t, cb.Execute(func() error { return nil }))
+		assert.Equal(t, StateClosed, cb.State())
+	})
+
+	t.Run("half-open failure reopens", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(time.Time{})
+		cb := NewCircuitBreaker(BreakerSettings{FailureThreshold: 1, CoolDown: time.Minute, Clock: clock})
+
+		_ = cb.Execute(func() error { return err1 })
+		clock.Advance(time.Minute)
+
+		assert.ErrorIs(t, cb.Execute(func() error { return err2 }), err2)
+		assert.Equal(t, StateOpen, cb.State())
+	})
+
+	t.Run("half-open panic reopens", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(time.Time{})
+		cb := NewCircuitBreaker(BreakerSettings{FailureThreshold: 1, CoolDown: time.Minute, Clock: clock})
+
+		_ = cb.Execute(func() error { return err1 })
+		clock.Advance(time.Minute)
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = cb.Execute(func() error { panic("boom") })
+		})
+		assert.Equal(t, StateOpen, cb.State())
+
+		clock.Advance(time.Minute)
+		assert.NoError(t, cb.Execute(func() error { return nil }))
+		assert.Equal(t, StateClosed, cb.State())
+	})
+
+	t.Run("half-open limits trial calls", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(time.Time{})
+		cb := NewCircuitBreaker(BreakerSettings{
+			FailureThreshold: 1,
+			SuccessThreshold: 2,
+			CoolDown:         time.Minute,
+			Clock:            clock,
+		})
+
+		_ = cb.Execute(func() error { return err1 })
+		clock.Advance(time.Minute)
+
+		err := cb.Execute(func() error {
+			return cb.Execute(func() error { return nil })
+		})
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, StateOpen, cb.State())
+	})
+
+	t.Run("success threshold", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(time.Time{})
+		cb := NewCircuitBreaker(BreakerSettings{
+			FailureThreshold: 1,
+			SuccessThreshold: 2,
+			CoolDown:         time.Minute,
+			Clock:            clock,
+		})
+
+		_ = cb.Execute(func() error { return err1 })
+		clock.Advance(time.Minute)
+
+		assert.NoError(t, cb.Execute(func() error { return nil }))
+		assert.Equal(t, StateHalfOpen, cb.State())
+		assert.NoError(t, cb.Execute(func() error { return nil }))
+		assert.Equal(t, StateClosed, cb.State())
+	})
+
+	t.Run("state change callback", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(time.Time{})
+		var transitions []string
+		cb := NewCircuitBreaker(BreakerSettings{
+			FailureThreshold: 1,
+			CoolDown:         time.Minute,
+			Clock:            clock,
+			OnStateChange: func(from, to State) {
+				transitions = append(transitions, from.String()+"->"+to.String())
+			},
+		})
+
+		_ = cb.Execute(func() error { return err1 })
+		clock.Advance(time.Minute)
+		_ = cb.Execute(func() error { return nil })
+
+		assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, transitions)
+	})
+
+	t.Run("ignores context cancellation", func(t *testing.T) {
+		cb := NewCircuitBreaker(BreakerSettings{FailureThreshold: 1, Clock: resulttest.NewFakeClock(time.Time{})})
+
+		_ = cb.Execute(func() error { return context.Canceled })
+		assert.Equal(t, StateClosed, cb.State())
+	})
+
+	t.Run("cancellation between failures is ignored", func(t *testing.T) {
+		cb := NewCircuitBreaker(BreakerSettings{FailureThreshold: 2, Clock: resulttest.NewFakeClock(time.Time{})})
+
+		_ = cb.Execute(func() error { return err1 })
+		_ = cb.Execute(func() error { return context.Canceled })
+		assert.Equal(t, StateClosed, cb.State())
+
+		_ = cb.Execute(func() error { return err1 })
+		assert.Equal(t, StateOpen, cb.State())
+	})
+
+	t.Run("cancelled half-open trial is ignored", func(t *testing.T) {
+		clock := resulttest.NewFakeClock(time.Time{})
+		cb := NewCircuitBreaker(BreakerSettings{FailureThreshold: 1, CoolDown: time.Minute, Clock: clock})
+
+		_ = cb.Execute(func() error { return err1 })
+		clock.Advance(time.Minute)
+
+		assert.ErrorIs(t, cb.Execute(func() error { return context.Canceled }), context.Canceled)
+		assert.Equal(t, StateHalfOpen, cb.State())
+
+		// The cancelled trial released its slot, so the next trial is let through.
+		assert.NoError(t, cb.Execute(func() error { return nil }))
+		assert.Equal(t, StateClosed, cb.State())
+	})
+
+	t.Run("custom failure classifier", func(t *testing.T) {
+		cb := NewCircuitBreaker(BreakerSettings{
+			FailureThreshold: 1,
+			Clock:            resulttest.NewFakeClock(time.Time{}),
+			IsFailure: func(err error) bool {
+				return !errors.Is(err, err3)
+			},
+		})
+
+		_ = cb.Execute(func() error { return err3 })
+		assert.Equal(t, StateClosed, cb.State())
+	})
+
+	t.Run("WithBreaker", func(t *testing.T) {
+		cb := NewCircuitBreaker(BreakerSettings{FailureThreshold: 1, Clock: resulttest.NewFakeClock(time.Time{})})
+		group, _ := WithErrorsThreshold[int](context.Background(), 3)
+
+		group.Go(WithBreaker(cb, func() ([]int, error) {
+			return nil, err1
+		}))
+		_, _ = group.Wait()
+
+		group.Go(WithBreaker(cb, func() ([]int, error) {
+			return []int{1}, nil
+		}))
+		results, err := group.Wait()
+
+		assert.Empty(t, results)
+		assert.True(t, errors.Is(err, ErrCircuitOpen), "Expected error to be: %v, got: %v", ErrCircuitOpen, err)
+	})
+
+	t.Run("panics on negative thresholds", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewCircuitBreaker(BreakerSettings{FailureThreshold: -1})
+		})
+	})
+}
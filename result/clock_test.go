@@ -0,0 +1,21 @@
+package result
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neurocode-io/go-pkgs/result/resulttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemClock(t *testing.T) {
+	var clock Clock = SystemClock{}
+
+	before := time.Now()
+	assert.False(t, clock.Now().Before(before))
+
+	fired := <-clock.After(time.Millisecond)
+	assert.False(t, fired.Before(before.Add(time.Millisecond)))
+
+	var _ Clock = resulttest.NewFakeClock(time.Time{})
+}
@@ -0,0 +1,184 @@
+package result
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoFutures is returned by AwaitAny when it is called without futures.
+var ErrNoFutures = errors.New("no futures to await")
+
+/*
+Future is the eventual result of an asynchronous computation started with Async.
+
+Example
+
+	future := result.Async(ctx, func(ctx context.Context) (int, error) {
+		return compute(ctx)
+	})
+	// do other work
+	value, err := future.Await(ctx)
+*/
+type Future[T any] struct {
+	ctx   context.Context
+	done  chan struct{}
+	value T
+	err   error
+}
+
+/*
+Async starts fn in a new goroutine and returns a Future for its result.
+
+Example
+
+	future := result.Async(ctx, func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+*/
+func Async[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Future[T] {
+	f := &Future[T]{ctx: ctx, done: make(chan struct{})}
+
+	go func() {
+		defer close(f.done)
+
+		f.value, f.err = fn(ctx)
+	}()
+
+	return f
+}
+
+// Done returns a channel that is closed once the computation has completed.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+/*
+Await blocks until the computation has completed or ctx is done, whichever happens first.
+
+Example
+
+	value, err := future.Await(ctx)
+	if err != nil {
+		// handle error
+	}
+*/
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var value T
+
+		return value, ctx.Err()
+	}
+}
+
+/*
+Then returns a Future that runs fn with the value of f once it has completed successfully.
+If f fails, the returned Future fails with the same error without calling fn.
+
+Example
+
+	ids := result.Async(ctx, fetchIDs)
+	users := result.Then(ids, func(ctx context.Context, ids []int) ([]User, error) {
+		return fetchUsers(ctx, ids)
+	})
+	list, err := users.Await(ctx)
+*/
+func Then[T, U any](f *Future[T], fn func(ctx context.Context, value T) (U, error)) *Future[U] {
+	return Async(f.ctx, func(ctx context.Context) (U, error) {
+		value, err := f.Await(ctx)
+		if err != nil {
+			var zero U
+
+			return zero, err
+		}
+
+		return fn(ctx, value)
+	})
+}
+
+/*
+AwaitAll waits for all futures and returns their values in order. Failed futures are left out of the
+values and their errors are returned together, like the errors returned by Group.Wait. If ctx is done
+first, it stops waiting and returns what it has collected so far, with ctx.Err() as the last error.
+
+Example
+
+	values, err := result.AwaitAll(ctx, future1, future2)
+	if err != nil {
+		fmt.Println("Wrapped errors", err.Unwrap())
+	}
+*/
+func AwaitAll[T any](ctx context.Context, futures ...*Future[T]) ([]T, errorWithUnwrap) {
+	values := make([]T, 0, len(futures))
+
+	var errs []error
+
+	for _, f := range futures {
+		value, err := f.Await(ctx)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+				return values, &multiError{errs: append(errs, ctxErr)}
+			}
+
+			errs = append(errs, err)
+
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	if len(errs) == 0 {
+		return values, nil
+	}
+
+	return values, &multiError{errs: errs}
+}
+
+/*
+AwaitAny returns the value of the first future that completes successfully. If all futures fail,
+their errors are returned together, like the errors returned by Group.Wait.
+
+Example
+
+	value, err := result.AwaitAny(ctx, primary, replica)
+*/
+func AwaitAny[T any](ctx context.Context, futures ...*Future[T]) (T, error) {
+	var value T
+
+	if len(futures) == 0 {
+		return value, ErrNoFutures
+	}
+
+	completed := make(chan *Future[T], len(futures))
+	stop := make(chan struct{})
+	defer close(stop)
+
+	for _, f := range futures {
+		go func() {
+			select {
+			case <-f.done:
+				completed <- f
+			case <-stop:
+			}
+		}()
+	}
+
+	errs := make([]error, 0, len(futures))
+	for range futures {
+		select {
+		case f := <-completed:
+			if f.err == nil {
+				return f.value, nil
+			}
+
+			errs = append(errs, f.err)
+		case <-ctx.Done():
+			return value, ctx.Err()
+		}
+	}
+
+	return value, &multiError{errs: errs}
+}
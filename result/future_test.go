@@ -0,0 +1,189 @@
+package result
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuture(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Await", func(t *testing.T) {
+		t.Parallel()
+		f := Async(context.Background(), func(context.Context) (int, error) {
+			return 42, nil
+		})
+
+		value, err := f.Await(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, value)
+	})
+
+	t.Run("Await error", func(t *testing.T) {
+		t.Parallel()
+		f := Async(context.Background(), func(context.Context) (int, error) {
+			return 0, err1
+		})
+
+		_, err := f.Await(context.Background())
+
+		assert.ErrorIs(t, err, err1)
+	})
+
+	t.Run("Await context done", func(t *testing.T) {
+		t.Parallel()
+		release := make(chan struct{})
+		defer close(release)
+
+		f := Async(context.Background(), func(context.Context) (int, error) {
+			<-release
+
+			return 42, nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := f.Await(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Done", func(t *testing.T) {
+		t.Parallel()
+		f := Async(context.Background(), func(context.Context) (int, error) {
+			return 42, nil
+		})
+
+		select {
+		case <-f.Done():
+		case <-time.After(time.Second):
+			t.Fatal("expected the future to complete")
+		}
+	})
+
+	t.Run("Then", func(t *testing.T) {
+		t.Parallel()
+		f := Async(context.Background(), func(context.Context) (int, error) {
+			return 21, nil
+		})
+		g := Then(f, func(_ context.Context, i int) (string, error) {
+			return strconv.Itoa(i * 2), nil
+		})
+
+		value, err := g.Await(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "42", value)
+	})
+
+	t.Run("Then error", func(t *testing.T) {
+		t.Parallel()
+		called := false
+		f := Async(context.Background(), func(context.Context) (int, error) {
+			return 0, err1
+		})
+		g := Then(f, func(_ context.Context, i int) (string, error) {
+			called = true
+
+			return strconv.Itoa(i), nil
+		})
+
+		_, err := g.Await(context.Background())
+
+		assert.ErrorIs(t, err, err1)
+		assert.False(t, called)
+	})
+
+	t.Run("AwaitAll", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		values, err := AwaitAll(ctx,
+			Async(ctx, func(context.Context) (int, error) { return 1, nil }),
+			Async(ctx, func(context.Context) (int, error) { return 2, nil }),
+		)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2}, values)
+
+		values, err = AwaitAll(ctx,
+			Async(ctx, func(context.Context) (int, error) { return 1, nil }),
+			Async(ctx, func(context.Context) (int, error) { return 0, err1 }),
+			Async(ctx, func(context.Context) (int, error) { return 0, err2 }),
+		)
+
+		assert.NotNil(t, err)
+		assert.Len(t, err.Unwrap(), 2)
+		assert.True(t, errors.Is(err, err1), "Expected error to be: %v, got: %v", err1, err)
+		assert.True(t, errors.Is(err, err2), "Expected error to be: %v, got: %v", err2, err)
+		assert.Equal(t, []int{1}, values)
+	})
+
+	t.Run("AwaitAll stops when ctx is done", func(t *testing.T) {
+		t.Parallel()
+		release := make(chan struct{})
+		defer close(release)
+
+		blocked := func(context.Context) (int, error) {
+			<-release
+
+			return 0, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := Async(context.Background(), func(context.Context) (int, error) { return 1, nil })
+		_, _ = done.Await(context.Background())
+		cancel()
+
+		values, err := AwaitAll(ctx,
+			done,
+			Async(context.Background(), blocked),
+			Async(context.Background(), blocked),
+			Async(context.Background(), blocked),
+		)
+
+		assert.Len(t, err.Unwrap(), 1)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.LessOrEqual(t, len(values), 1)
+	})
+
+	t.Run("AwaitAny", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		release := make(chan struct{})
+		defer close(release)
+
+		value, err := AwaitAny(ctx,
+			Async(ctx, func(context.Context) (int, error) { return 0, err1 }),
+			Async(ctx, func(context.Context) (int, error) {
+				<-release
+
+				return 1, nil
+			}),
+			Async(ctx, func(context.Context) (int, error) { return 2, nil }),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("AwaitAny all failed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		_, err := AwaitAny(ctx,
+			Async(ctx, func(context.Context) (int, error) { return 0, err1 }),
+			Async(ctx, func(context.Context) (int, error) { return 0, err2 }),
+		)
+
+		assert.ErrorIs(t, err, err1)
+		assert.ErrorIs(t, err, err2)
+
+		_, err = AwaitAny[int](ctx)
+		assert.ErrorIs(t, err, ErrNoFutures)
+	})
+}
@@ -0,0 +1,143 @@
+package result
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+/*
+Option holds either a value or nothing. It marshals to JSON null when it holds nothing.
+
+Example
+
+	opt := result.OptionOf(slice.Find([]int{1, 2, 3}, func(i int) bool { return i > 1 }))
+	opt.UnwrapOr(0) // 2
+*/
+type Option[T any] struct {
+	value T
+	ok    bool
+}
+
+/*
+Some returns an Option holding the value.
+
+Example
+
+	opt := result.Some(42)
+	opt.IsSome() // true
+*/
+func Some[T any](value T) Option[T] {
+	return Option[T]{value: value, ok: true}
+}
+
+/*
+None returns an empty Option.
+
+Example
+
+	opt := result.None[int]()
+	opt.IsNone() // true
+*/
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+/*
+OptionOf converts a (value, ok) tuple into an Option.
+
+Example
+
+	opt := result.OptionOf(asyncMap.Load("a"))
+*/
+func OptionOf[T any](value T, ok bool) Option[T] {
+	if !ok {
+		return None[T]()
+	}
+
+	return Some(value)
+}
+
+// IsSome returns true if the Option holds a value.
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+// IsNone returns true if the Option holds nothing.
+func (o Option[T]) IsNone() bool {
+	return !o.ok
+}
+
+// IsZero returns true if the Option holds nothing.
+func (o Option[T]) IsZero() bool {
+	return !o.ok
+}
+
+/*
+Get returns the value and true if the Option holds a value, otherwise it returns the zero value and false.
+
+Example
+
+	value, ok := result.Some(42).Get()
+	// value == 42, ok == true
+*/
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+/*
+UnwrapOr returns the value of the Option or fallback if it holds nothing.
+
+Example
+
+	result.None[int]().UnwrapOr(1) // 1
+*/
+func (o Option[T]) UnwrapOr(fallback T) T {
+	if !o.ok {
+		return fallback
+	}
+
+	return o.value
+}
+
+/*
+MapOption applies fn to the value of the Option if it holds one.
+
+Example
+
+	opt := result.MapOption(result.Some(2), func(i int) int { return i * 2 })
+	// opt.Get() == 4, true
+*/
+func MapOption[T, U any](o Option[T], fn func(T) U) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+
+	return Some(fn(o.value))
+}
+
+// MarshalJSON encodes the value of the Option or null if it holds nothing.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if !o.ok {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.value) //nolint:wrapcheck
+}
+
+// UnmarshalJSON decodes null into an empty Option and anything else into an Option holding the value.
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = None[T]()
+
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	*o = Some(value)
+
+	return nil
+}
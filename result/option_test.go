@@ -0,0 +1,62 @@
+package result
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOption(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		o := Some(42)
+		value, ok := o.Get()
+
+		assert.True(t, o.IsSome())
+		assert.False(t, o.IsNone())
+		assert.True(t, ok)
+		assert.Equal(t, 42, value)
+		assert.Equal(t, 42, o.UnwrapOr(1))
+	})
+
+	t.Run("None", func(t *testing.T) {
+		o := None[int]()
+		_, ok := o.Get()
+
+		assert.False(t, o.IsSome())
+		assert.True(t, o.IsNone())
+		assert.False(t, ok)
+		assert.Equal(t, 1, o.UnwrapOr(1))
+	})
+
+	t.Run("OptionOf", func(t *testing.T) {
+		assert.Equal(t, Some("a"), OptionOf("a", true))
+		assert.Equal(t, None[string](), OptionOf("a", false))
+	})
+
+	t.Run("MapOption", func(t *testing.T) {
+		double := func(i int) int { return i * 2 }
+
+		assert.Equal(t, Some(4), MapOption(Some(2), double))
+		assert.Equal(t, None[int](), MapOption(None[int](), double))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		type payload struct {
+			Name Option[string] `json:"name"`
+			Age  Option[int]    `json:"age"`
+		}
+
+		data, err := json.Marshal(payload{Name: Some("Alice"), Age: None[int]()})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name":"Alice","age":null}`, string(data))
+
+		var decoded payload
+		err = json.Unmarshal([]byte(`{"name":null,"age":30}`), &decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, payload{Name: None[string](), Age: Some(30)}, decoded)
+
+		err = json.Unmarshal([]byte(`{"age":"thirty"}`), &decoded)
+		assert.Error(t, err)
+	})
+}
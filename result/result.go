@@ -36,7 +36,13 @@ type multiError struct {
 	errs []error
 }
 
+// Executor runs the tasks of a Group. By default every task runs in its own goroutine.
+type Executor interface {
+	Go(task func())
+}
+
 type Group[T any] struct {
+	executor  Executor
 	cancel    func()
 	results   []T
 	errs      []error
@@ -64,6 +70,19 @@ func WithErrorsThreshold[T any](ctx context.Context, threshold int) (Group[T], c
 	return Group[T]{cancel: cancel, threshold: threshold}, ctx
 }
 
+/*
+SetExecutor replaces the way the Group runs its tasks. It must be called before the first call to Go.
+It is mostly useful in tests, see the resulttest package for a deterministic scheduler.
+
+Example
+
+	scheduler := resulttest.NewScheduler()
+	group.SetExecutor(scheduler)
+*/
+func (g *Group[T]) SetExecutor(executor Executor) {
+	g.executor = executor
+}
+
 /*
 Go starts a goroutine that performs a given function and handles its results and errors.
 
@@ -77,12 +96,20 @@ Example
 func (g *Group[T]) Go(f func() ([]T, error)) {
 	g.wg.Add(1)
 
-	go func() {
+	task := func() {
 		defer g.wg.Done()
 
 		res, err := f()
 		g.processResult(res, err)
-	}()
+	}
+
+	if g.executor != nil {
+		g.executor.Go(task)
+
+		return
+	}
+
+	go task()
 }
 
 func (g *Group[T]) processResult(res []T, err error) {
@@ -0,0 +1,109 @@
+package resulttest
+
+import (
+	"sync"
+	"time"
+)
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+/*
+FakeClock is a clock that only moves when told to. It implements result.Clock.
+
+Example
+
+	clock := resulttest.NewFakeClock(time.Now())
+	breaker := result.NewCircuitBreaker(result.BreakerSettings{Clock: clock, CoolDown: time.Minute})
+	clock.Advance(time.Minute)
+*/
+type FakeClock struct {
+	now     time.Time
+	waiters []waiter
+	mutex   sync.Mutex
+}
+
+// NewFakeClock returns a new pointer to a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+/*
+After returns a channel that receives the current time once the clock has been advanced by d.
+
+Example
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-clock.After(10 * time.Millisecond):
+		return []int{1}, nil
+	}
+*/
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ch := make(chan time.Time, 1)
+	at := c.now.Add(d)
+
+	if d <= 0 {
+		ch <- c.now
+
+		return ch
+	}
+
+	c.waiters = append(c.waiters, waiter{at: at, ch: ch})
+
+	return ch
+}
+
+// Sleep blocks until the clock has been advanced by d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Waiters returns the number of pending After and Sleep calls. Tests use it to wait until tasks are blocked on the clock.
+func (c *FakeClock) Waiters() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.waiters)
+}
+
+/*
+Advance moves the clock forward by d and fires all After and Sleep calls that are due.
+
+Example
+
+	clock.Advance(time.Second)
+*/
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+
+	remaining := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			remaining = append(remaining, w)
+
+			continue
+		}
+
+		w.ch <- c.now
+	}
+
+	c.waiters = remaining
+}
@@ -0,0 +1,90 @@
+package resulttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	result "github.com/neurocode-io/go-pkgs/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Now and Advance", func(t *testing.T) {
+		c := NewFakeClock(start)
+		assert.Equal(t, start, c.Now())
+
+		c.Advance(time.Minute)
+		assert.Equal(t, start.Add(time.Minute), c.Now())
+	})
+
+	t.Run("After", func(t *testing.T) {
+		c := NewFakeClock(start)
+		ch := c.After(10 * time.Millisecond)
+		assert.Equal(t, 1, c.Waiters())
+
+		c.Advance(9 * time.Millisecond)
+		select {
+		case <-ch:
+			t.Fatal("expected After not to fire yet")
+		default:
+		}
+
+		c.Advance(time.Millisecond)
+		assert.Equal(t, start.Add(10*time.Millisecond), <-ch)
+		assert.Equal(t, 0, c.Waiters())
+
+		assert.Equal(t, start.Add(10*time.Millisecond), <-c.After(0))
+	})
+
+	t.Run("Sleep", func(t *testing.T) {
+		c := NewFakeClock(start)
+		done := make(chan struct{})
+
+		go func() {
+			c.Sleep(time.Second)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool { return c.Waiters() == 1 }, time.Second, time.Millisecond)
+		c.Advance(time.Second)
+		<-done
+	})
+
+	t.Run("drives timed tasks of a group", func(t *testing.T) {
+		c := NewFakeClock(start)
+		s := NewScheduler()
+		group, ctx := result.WithErrorsThreshold[int](context.Background(), 1)
+		group.SetExecutor(s)
+
+		group.Go(func() ([]int, error) {
+			c.Sleep(10 * time.Millisecond)
+
+			return nil, err1
+		})
+		group.Go(func() ([]int, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-c.After(20 * time.Millisecond):
+				return []int{1}, nil
+			}
+		})
+
+		first := s.Start(0)
+		second := s.Start(1)
+
+		assert.Eventually(t, func() bool { return c.Waiters() == 2 }, time.Second, time.Millisecond)
+		c.Advance(10 * time.Millisecond)
+		<-first
+		<-second
+
+		results, err := group.Wait()
+
+		assert.Empty(t, results)
+		assert.Len(t, err.Unwrap(), 1)
+		assert.ErrorIs(t, err, err1)
+	})
+}
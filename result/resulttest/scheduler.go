@@ -0,0 +1,221 @@
+// Package resulttest provides a deterministic scheduler and a fake clock for testing code built on the result package.
+//
+// Example
+//
+//	import (
+//		result "github.com/neurocode-io/go-pkgs/result"
+//		"github.com/neurocode-io/go-pkgs/result/resulttest"
+//	)
+//
+//	scheduler := resulttest.NewScheduler()
+//	group, ctx := result.WithErrorsThreshold[int](context.Background(), 1)
+//	group.SetExecutor(scheduler)
+//	group.Go(worker1)
+//	group.Go(worker2)
+//	scheduler.RunTask(1) // worker2 runs to completion first
+//	scheduler.RunTask(0)
+//	results, err := group.Wait()
+package resulttest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+)
+
+type taskState int
+
+const (
+	pending taskState = iota
+	running
+	finished
+)
+
+type task struct {
+	fn    func()
+	done  chan struct{}
+	state taskState
+}
+
+/*
+Scheduler queues the tasks submitted to it instead of running them, so that a test decides when and in
+which order every task runs. Tasks are identified by their submission index, starting at 0.
+It implements result.Executor.
+
+Example
+
+	scheduler := resulttest.NewScheduler()
+	group.SetExecutor(scheduler)
+	group.Go(worker)
+	scheduler.RunAll()
+	results, err := group.Wait()
+*/
+type Scheduler struct {
+	tasks []*task
+	mutex sync.Mutex
+}
+
+// NewScheduler returns a new pointer to a Scheduler.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Go queues the task. It implements result.Executor.
+func (s *Scheduler) Go(fn func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tasks = append(s.tasks, &task{fn: fn, done: make(chan struct{})})
+}
+
+// Len returns the number of tasks submitted so far.
+func (s *Scheduler) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.tasks)
+}
+
+// Pending returns the submission indexes of the tasks that have not been started yet.
+func (s *Scheduler) Pending() []int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result []int
+	for i, t := range s.tasks {
+		if t.state == pending {
+			result = append(result, i)
+		}
+	}
+
+	return result
+}
+
+/*
+Step runs the oldest pending task to completion in the calling goroutine. It returns false if there is no pending task.
+
+Example
+
+	for scheduler.Step() {
+		// inspect state between tasks
+	}
+*/
+func (s *Scheduler) Step() bool {
+	pending := s.Pending()
+	if len(pending) == 0 {
+		return false
+	}
+
+	s.RunTask(pending[0])
+
+	return true
+}
+
+/*
+RunTask runs the task with the given submission index to completion in the calling goroutine.
+It panics if the task does not exist or has already been started.
+
+Example
+
+	scheduler.RunTask(2)
+*/
+func (s *Scheduler) RunTask(i int) {
+	<-s.Start(i)
+}
+
+/*
+Start runs the task with the given submission index in a new goroutine and returns a channel that is
+closed once the task has completed. It allows tests to have several tasks in flight at once.
+It panics if the task does not exist or has already been started.
+
+Example
+
+	first := scheduler.Start(0)
+	second := scheduler.Start(1)
+	<-first
+	<-second
+*/
+func (s *Scheduler) Start(i int) <-chan struct{} {
+	t := s.claim(i)
+
+	go func() {
+		defer close(t.done)
+		defer s.finish(t)
+
+		t.fn()
+	}()
+
+	return t.done
+}
+
+/*
+RunOrder runs the tasks with the given submission indexes one after another.
+
+Example
+
+	scheduler.RunOrder(2, 0, 1)
+*/
+func (s *Scheduler) RunOrder(order ...int) {
+	for _, i := range order {
+		s.RunTask(i)
+	}
+}
+
+/*
+RunAll runs pending tasks in submission order until there are none left, including tasks submitted by other tasks.
+
+Example
+
+	scheduler.RunAll()
+	results, err := group.Wait()
+*/
+func (s *Scheduler) RunAll() {
+	for s.Step() {
+	}
+}
+
+/*
+RunRandom runs all pending tasks one after another in an order derived from seed and returns that order.
+Logging the seed of a failing run allows the same interleaving to be replayed.
+
+Example
+
+	order := scheduler.RunRandom(seed)
+	t.Logf("seed %d ran tasks in order %v", seed, order)
+*/
+func (s *Scheduler) RunRandom(seed uint64) []int {
+	order := s.Pending()
+	rng := rand.New(rand.NewPCG(seed, seed)) //nolint:gosec
+
+	rng.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+	s.RunOrder(order...)
+
+	return order
+}
+
+func (s *Scheduler) claim(i int) *task {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if i < 0 || i >= len(s.tasks) {
+		panic(fmt.Sprintf("resulttest: task %d does not exist", i))
+	}
+
+	t := s.tasks[i]
+	if t.state != pending {
+		panic(fmt.Sprintf("resulttest: task %d has already been started", i))
+	}
+
+	t.state = running
+
+	return t
+}
+
+func (s *Scheduler) finish(t *task) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t.state = finished
+}
@@ -0,0 +1,130 @@
+package resulttest
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	result "github.com/neurocode-io/go-pkgs/result"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	err1 = errors.New("Error 1")
+	err2 = errors.New("Error 2")
+)
+
+func TestScheduler(t *testing.T) {
+	t.Run("queues tasks", func(t *testing.T) {
+		s := NewScheduler()
+		ran := 0
+		s.Go(func() { ran++ })
+		s.Go(func() { ran++ })
+
+		assert.Equal(t, 2, s.Len())
+		assert.Equal(t, []int{0, 1}, s.Pending())
+		assert.Equal(t, 0, ran)
+
+		assert.True(t, s.Step())
+		assert.Equal(t, 1, ran)
+		assert.Equal(t, []int{1}, s.Pending())
+
+		s.RunAll()
+		assert.Equal(t, 2, ran)
+		assert.False(t, s.Step())
+	})
+
+	t.Run("RunOrder", func(t *testing.T) {
+		s := NewScheduler()
+		var order []int
+		for i := 0; i < 3; i++ {
+			s.Go(func() { order = append(order, i) })
+		}
+
+		s.RunOrder(2, 0, 1)
+		assert.Equal(t, []int{2, 0, 1}, order)
+	})
+
+	t.Run("RunRandom is reproducible", func(t *testing.T) {
+		run := func(seed uint64) ([]int, []int) {
+			s := NewScheduler()
+			var order []int
+			for i := 0; i < 8; i++ {
+				s.Go(func() { order = append(order, i) })
+			}
+
+			return s.RunRandom(seed), order
+		}
+
+		first, ran := run(42)
+		second, _ := run(42)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, first, ran)
+		assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, first)
+	})
+
+	t.Run("Start runs tasks concurrently", func(t *testing.T) {
+		s := NewScheduler()
+		release := make(chan struct{})
+		var ran atomic.Int32
+		s.Go(func() {
+			<-release
+			ran.Add(1)
+		})
+		s.Go(func() {
+			close(release)
+			ran.Add(1)
+		})
+
+		first := s.Start(0)
+		s.RunTask(1)
+		<-first
+
+		assert.Equal(t, int32(2), ran.Load())
+	})
+
+	t.Run("panics on unknown or started tasks", func(t *testing.T) {
+		s := NewScheduler()
+		s.Go(func() {})
+		s.RunTask(0)
+
+		assert.Panics(t, func() { s.RunTask(0) })
+		assert.Panics(t, func() { s.RunTask(1) })
+	})
+
+	t.Run("threshold cancellation order", func(t *testing.T) {
+		s := NewScheduler()
+		group, ctx := result.WithErrorsThreshold[int](context.Background(), 2)
+		group.SetExecutor(s)
+
+		group.Go(func() ([]int, error) { return nil, err1 })
+		group.Go(func() ([]int, error) { return []int{1}, nil })
+		group.Go(func() ([]int, error) { return nil, err2 })
+		group.Go(func() ([]int, error) {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			return []int{2}, nil
+		})
+
+		s.RunTask(0)
+		assert.NoError(t, ctx.Err())
+
+		s.RunTask(1)
+		assert.NoError(t, ctx.Err())
+
+		s.RunTask(2)
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+		s.RunTask(3)
+		results, err := group.Wait()
+
+		assert.Equal(t, []int{1}, results)
+		assert.Len(t, err.Unwrap(), 2)
+		assert.ErrorIs(t, err, err1)
+		assert.ErrorIs(t, err, err2)
+	})
+}
@@ -0,0 +1,166 @@
+package result
+
+/*
+Result holds either the value or the error of a single computation.
+
+Example
+
+	r := result.Try(strconv.Atoi("42"))
+	n := r.UnwrapOr(0)
+	// n == 42
+*/
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+/*
+Ok returns a successful Result holding the value.
+
+Example
+
+	r := result.Ok(42)
+	r.IsOk() // true
+*/
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+/*
+Err returns a failed Result holding the error. It panics if err is nil; use Try for errors that may be nil.
+
+Example
+
+	r := result.Err[int](errors.New("boom"))
+	r.IsErr() // true
+*/
+func Err[T any](err error) Result[T] {
+	if err == nil {
+		panic("result.Err requires a non-nil error")
+	}
+
+	return Result[T]{err: err}
+}
+
+/*
+Try converts a (value, error) tuple into a Result.
+
+Example
+
+	r := result.Try(strconv.Atoi("x"))
+	r.IsErr() // true
+*/
+func Try[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+
+	return Ok(value)
+}
+
+// IsOk returns true if the Result holds a value.
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr returns true if the Result holds an error.
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+/*
+Get returns the value and the error of the Result.
+
+Example
+
+	value, err := result.Ok(42).Get()
+	// value == 42, err == nil
+*/
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Err returns the error of the Result or nil if it holds a value.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+/*
+UnwrapOr returns the value of the Result or fallback if it holds an error.
+
+Example
+
+	result.Err[int](errors.New("boom")).UnwrapOr(1) // 1
+*/
+func (r Result[T]) UnwrapOr(fallback T) T {
+	if r.err != nil {
+		return fallback
+	}
+
+	return r.value
+}
+
+/*
+Map applies fn to the value of a successful Result. Errors are passed through unchanged.
+
+Example
+
+	r := result.Map(result.Ok(2), func(i int) string { return strconv.Itoa(i * 2) })
+	// r.Get() == "4", nil
+*/
+func Map[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+
+	return Ok(fn(r.value))
+}
+
+/*
+FlatMap applies fn to the value of a successful Result and returns its Result. Errors are passed through unchanged.
+
+Example
+
+	r := result.FlatMap(result.Ok("42"), func(s string) result.Result[int] {
+		return result.Try(strconv.Atoi(s))
+	})
+	// r.Get() == 42, nil
+*/
+func FlatMap[T, U any](r Result[T], fn func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+
+	return fn(r.value)
+}
+
+/*
+Collect turns a slice of Results into a Result of a slice. If any Result failed, the returned Result
+holds all errors, which can be retrieved with Unwrap() []error, like the errors returned by Group.Wait.
+
+Example
+
+	r := result.Collect([]result.Result[int]{result.Ok(1), result.Ok(2)})
+	// r.Get() == []int{1, 2}, nil
+*/
+func Collect[T any](results []Result[T]) Result[[]T] {
+	values := make([]T, 0, len(results))
+
+	var errs []error
+
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+
+			continue
+		}
+
+		values = append(values, r.value)
+	}
+
+	if len(errs) > 0 {
+		return Err[[]T](&multiError{errs: errs})
+	}
+
+	return Ok(values)
+}
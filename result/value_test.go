@@ -0,0 +1,70 @@
+package result
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	t.Run("Ok", func(t *testing.T) {
+		r := Ok(42)
+		value, err := r.Get()
+
+		assert.True(t, r.IsOk())
+		assert.False(t, r.IsErr())
+		assert.NoError(t, err)
+		assert.NoError(t, r.Err())
+		assert.Equal(t, 42, value)
+		assert.Equal(t, 42, r.UnwrapOr(1))
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		r := Err[int](err1)
+		_, err := r.Get()
+
+		assert.False(t, r.IsOk())
+		assert.True(t, r.IsErr())
+		assert.ErrorIs(t, err, err1)
+		assert.ErrorIs(t, r.Err(), err1)
+		assert.Equal(t, 1, r.UnwrapOr(1))
+		assert.Panics(t, func() { Err[int](nil) })
+	})
+
+	t.Run("Try", func(t *testing.T) {
+		assert.Equal(t, 42, Try(strconv.Atoi("42")).UnwrapOr(0))
+		assert.True(t, Try(strconv.Atoi("x")).IsErr())
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		double := func(i int) string { return strconv.Itoa(i * 2) }
+
+		assert.Equal(t, "4", Map(Ok(2), double).UnwrapOr(""))
+		assert.ErrorIs(t, Map(Err[int](err1), double).Err(), err1)
+	})
+
+	t.Run("FlatMap", func(t *testing.T) {
+		parse := func(s string) Result[int] { return Try(strconv.Atoi(s)) }
+
+		assert.Equal(t, 42, FlatMap(Ok("42"), parse).UnwrapOr(0))
+		assert.True(t, FlatMap(Ok("x"), parse).IsErr())
+		assert.ErrorIs(t, FlatMap(Err[string](err1), parse).Err(), err1)
+	})
+
+	t.Run("Collect", func(t *testing.T) {
+		values, err := Collect([]Result[int]{Ok(1), Ok(2), Ok(3)}).Get()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, values)
+
+		_, err = Collect([]Result[int]{Ok(1), Err[int](err1), Err[int](err2)}).Get()
+
+		var unwrapper errorWithUnwrap
+		assert.True(t, errors.As(err, &unwrapper))
+		assert.Len(t, unwrapper.Unwrap(), 2)
+		assert.ErrorIs(t, err, err1)
+		assert.ErrorIs(t, err, err2)
+	})
+}